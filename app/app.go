@@ -6,29 +6,121 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/mui87/atctest/atcoder"
 )
 
-const baseURL = "https://atcoder.jp"
+const (
+	baseURL = "https://atcoder.jp"
+
+	defaultTimeLimit = 2 * time.Second
+
+	submissionPollInterval = 2 * time.Second
+)
+
+var defaultTolerance = atcoder.Tolerance{Absolute: 1e-6, Relative: 1e-6}
+
+// commands given as the first argument. test is executed if omitted.
+const (
+	commandTest     = "test"
+	commandSubmit   = "submit"
+	commandLogin    = "login"
+	commandLogout   = "logout"
+	commandNew      = "new"
+	commandDownload = "download"
+	commandAddCase  = "add-case"
+	commandStress   = "stress"
+	commandShrink   = "shrink"
+	commandGen      = "gen"
+)
+
+var commands = []string{commandTest, commandSubmit, commandLogin, commandLogout, commandNew, commandDownload, commandAddCase, commandStress, commandShrink, commandGen}
+
+// flags available for commands. other flags are rejected so that they are not silently ignored
+var (
+	problemFlags = []string{"contest", "problem", "url", "nocache", "pattern", "username", "password"}
+	runFlags     = []string{"command", "build", "file", "lang", "judge", "abserror", "relerror", "timelimit", "memorylimit", "rlimit", "diff", "maxlines", "format", "tests"}
+	testFlags    = concat(problemFlags, runFlags, []string{"parallel", "interactor", "trials", "querylimit"})
+	shrinkFlags  = []string{"brute", "shrink", "shrinkruns", "validator"}
+
+	commandFlags = map[string][]string{
+		commandTest:     testFlags,
+		commandSubmit:   concat(testFlags, []string{"submitlang", "nocheck", "nowait"}),
+		commandLogin:    {"username", "password"},
+		commandLogout:   {},
+		commandNew:      {"contest", "lang", "template", "dir", "layout", "nocache", "username", "password"},
+		commandDownload: concat(problemFlags, []string{"file", "dir", "layout"}),
+		commandAddCase:  {"file", "tests", "in", "out", "name", "force"},
+		commandStress:   concat(runFlags, shrinkFlags, []string{"gen", "spec", "iterations", "seed", "force"}),
+		commandShrink:   concat(runFlags, shrinkFlags, []string{"in", "name", "force", "spec"}),
+		commandGen:      {"spec", "seed"},
+	}
+	// flags available for all commands
+	commonFlags = []string{"color"}
+)
+
+// shrinkNone disables shrinking of failing inputs
+const shrinkNone = "none"
+
+// local test cases are read from this directory in the directory of the source file
+const localCaseDirName = "tests"
 
 type App struct {
 	client  *atcoder.Client
 	checker *atcoder.Checker
 
+	subcommand string
+
 	contest string
 	problem string
 	command string
+	build   string
+
+	file   string
+	preset *Preset
+
+	submitLanguage string
+	noCheck        bool
+	noWait         bool
 
-	username string
-	password string
+	template     string
+	dir          string
+	sampleLayout string
+
+	testsDir   string
+	caseInput  string
+	caseOutput string
+	caseName   string
+	force      bool
+
+	stressOption atcoder.StressOption
+	shrinkOption atcoder.ShrinkOption
+
+	username        string
+	password        string
+	sessionFilePath string
+	prompt          credentialPrompt
 
 	contestURL string
 	problemURL string
 
+	judgeName string
+	tolerance atcoder.Tolerance
+
+	timeLimit   time.Duration
+	memoryLimit int64
+
+	interactor string
+	trials     int
+	queryLimit int
+
 	outStream io.Writer
 	errStream io.Writer
 }
@@ -36,34 +128,280 @@ type App struct {
 func New(args []string, outStream, errStream io.Writer) (*App, error) {
 	var errBuff bytes.Buffer
 
+	subcommand := commandTest
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") && !isSourceFile(args[1]) {
+		subcommand = args[1]
+		args = args[1:]
+		if !isCommand(subcommand) {
+			return nil, fmt.Errorf("unknown command '%s'. available commands: %s", subcommand, strings.Join(commands, ", "))
+		}
+	}
+	// the source file can be given as the argument instead of -file, before or after flags. e.g.) atctest a.cpp
+	// it is the contest for new command. e.g.) atctest new abc300
+	var positionalArg string
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		positionalArg = args[1]
+		args = args[1:]
+	}
+
 	flags := flag.NewFlagSet("atctest", flag.ContinueOnError)
 	flags.SetOutput(&errBuff)
+	available := map[string]bool{}
+	for _, name := range concat(commandFlags[subcommand], commonFlags) {
+		available[name] = true
+	}
 	flags.Usage = func() {
 		_, _ = fmt.Fprintln(&errBuff, helpMessage)
-		flags.PrintDefaults()
+		printDefaults(&errBuff, flags, available)
 	}
 
 	var (
 		contest    string
 		problem    string
 		command    string
+		build      string
+		file       string
+		language   string
 		username   string
 		password   string
 		problemURL string
 		nocache    bool
+		judgeName  string
+		absError   float64
+		relError   float64
+		timeLimit  time.Duration
+		memoryMB   int64
+		rlimit     bool
+		parallel   int
+		diffMode   string
+		maxLines   int
+		format     string
+		submitLang string
+		noCheck    bool
+		noWait     bool
+		interactor string
+		trials     int
+		queryLimit int
+		colorMode  string
+		pattern    string
+		template   string
+		dir        string
+		layout     string
+		testsDir   string
+		caseInput  string
+		caseOutput string
+		caseName   string
+		force      bool
+		generator  string
+		specText   string
+		reference  string
+		iterations int
+		seed       int64
+		shrink     string
+		shrinkRuns int
+		validator  string
 	)
 	flags.StringVar(&contest, "contest", "", "contest you are challenging. e.g.) ABC051")
 	flags.StringVar(&problem, "problem", "", "problem you are solving. e.g.) C")
 	flags.StringVar(&command, "command", "", "command to execute your program. e.g.) 'python c.py'")
-	flags.StringVar(&username, "username", "", "your username of atcoder account. e.g.) 'chokudai'")
-	flags.StringVar(&password, "password", "", "your password of atcoder account. e.g.) 'password'")
+	flags.StringVar(&build, "build", "", "command to build your program, which is executed once before running samples. e.g.) 'g++ -O2 c.cpp'")
+	flags.StringVar(&file, "file", "", "source file of your program, which can also be given as the argument. build/run commands are selected by its extension. e.g.) c.cpp")
+	flags.StringVar(&language, "lang", "", "language preset used for the source file instead of detecting it from the extension. e.g.) pypy")
+	flags.StringVar(&username, "username", "", fmt.Sprintf("your username of atcoder account, which takes precedence over %s and the config file. e.g.) 'chokudai'", usernameEnvName))
+	flags.StringVar(&password, "password", "", fmt.Sprintf("your password of atcoder account, which takes precedence over %s and the config file. they are recommended instead because options are left in your shell history. e.g.) 'password'", passwordEnvName))
 	flags.StringVar(&problemURL, "url", "", "url of the problem page. e.g.) 'https://abc051.contest.atcoder.jp/tasks/abc051_c'")
 	flags.BoolVar(&nocache, "nocache", false, "if set, local cache of samples is not used.")
+	flags.StringVar(&judgeName, "judge", "", fmt.Sprintf("how to compare outputs. one of %s, or command to execute your special judge program. 'float' is used if allowed error is specified or detected, otherwise 'exact'. e.g.) 'python judge.py'", strings.Join(atcoder.JudgeNames, ", ")))
+	flags.Float64Var(&absError, "abserror", 0, "allowed absolute error of floating-point outputs. detected from the problem page if not set. e.g.) 1e-6")
+	flags.Float64Var(&relError, "relerror", 0, "allowed relative error of floating-point outputs. detected from the problem page if not set. e.g.) 1e-6")
+	flags.DurationVar(&timeLimit, "timelimit", 0, "time limit of your program for each sample. the time limit of the problem is used if not set. e.g.) 500ms")
+	flags.Int64Var(&memoryMB, "memorylimit", 0, "memory limit of your program in MB. the memory limit of the problem is used if not set. e.g.) 256")
+	flags.BoolVar(&rlimit, "rlimit", false, "if set, address space of your program is limited to the memory limit by rlimit.")
+	flags.IntVar(&parallel, "parallel", 1, "number of samples run concurrently. note that heavy parallelism can slow down each run.")
+	flags.StringVar(&diffMode, "diff", atcoder.DiffUnified, fmt.Sprintf("how to show the difference of outputs for failed samples. one of %s.", strings.Join(atcoder.DiffModes, ", ")))
+	flags.IntVar(&maxLines, "maxlines", 50, "max number of lines shown for each of the input and the diff of failed samples. unlimited if 0.")
+	flags.StringVar(&format, "format", atcoder.FormatHuman, fmt.Sprintf("format of results. one of %s. other messages are written to stderr unless 'human'.", strings.Join(atcoder.FormatNames, ", ")))
+	flags.StringVar(&submitLang, "submitlang", "", "language of the submission, which is the id or a part of the name on the submit page. selected by the language preset if not set. e.g.) 4003, 'C++ (GCC'")
+	flags.BoolVar(&noCheck, "nocheck", false, "if set, the submission is made without checking samples.")
+	flags.BoolVar(&noWait, "nowait", false, "if set, atctest exits right after the submission without waiting for the judge.")
+	flags.StringVar(&interactor, "interactor", "", "command to execute the interactor for interactive problems. the trial number is passed as its argument. e.g.) 'python interactor.py'")
+	flags.IntVar(&trials, "trials", 1, "number of trials for interactive problems.")
+	flags.IntVar(&queryLimit, "querylimit", 0, "max number of queries (lines starting with '?') for interactive problems. unlimited if not set.")
+	flags.StringVar(&pattern, "pattern", "", fmt.Sprintf("path pattern of source files from which the contest and the problem are inferred if they are not specified. %s are tried if not set. e.g.) '{contest}/{problem}/main.*'", strings.Join(defaultPathPatterns, ", ")))
+	flags.StringVar(&template, "template", "", "template file copied into the directory of each problem by new command. the template of the language preset is used if not set. e.g.) ~/template.cpp")
+	flags.StringVar(&dir, "dir", "", "directory in which new command creates the directory of the contest (the current directory by default), or download command writes samples (samples/, or test/ for 'oj' layout by default).")
+	flags.StringVar(&layout, "layout", atcoder.SampleLayoutTxt, fmt.Sprintf("layout of sample files written by new/download commands. one of %s. 'txt' is in_1.txt/out_1.txt and 'oj' is sample-1.in/sample-1.out like online-judge-tools.", strings.Join(atcoder.SampleLayouts, ", ")))
+	flags.StringVar(&testsDir, "tests", "", fmt.Sprintf("directory of local test cases added by you, which are checked with samples. %s/ in the directory of the source file (or the current directory) is used if not set.", localCaseDirName))
+	flags.StringVar(&caseInput, "in", "", "input file of the test case added by add-case command. e.g.) in.txt")
+	flags.StringVar(&caseOutput, "out", "", "expected output file of the test case added by add-case command. e.g.) out.txt")
+	flags.StringVar(&caseName, "name", "", "name of the test case added by add-case command. numbered like case_1 if not set. e.g.) max")
+	flags.BoolVar(&force, "force", false, "if set, add-case/stress/shrink commands overwrite the existing test case of the same name.")
+	flags.StringVar(&generator, "gen", "", "command to generate a random input for stress command. the seed is passed as its argument. e.g.) 'python gen.py'")
+	flags.StringVar(&specText, "spec", "", "spec of random inputs for gen/stress commands, or the file of it. used by stress command instead of -gen, and keeps inputs shrunk by stress/shrink commands valid. e.g.) 'N int [1,2e5]; A [N]int [1,1e9]; edges tree N'")
+	flags.StringVar(&reference, "brute", "", "command to execute the naive solution whose output is expected in stress command. e.g.) 'python brute.py'")
+	flags.IntVar(&iterations, "iterations", 100, "max number of random inputs tried by stress command.")
+	flags.Int64Var(&seed, "seed", 1, "seed of the random input of gen command, or the first random input of stress command which is incremented for each input.")
+	flags.StringVar(&shrink, "shrink", strings.Join(atcoder.DefaultShrinkerNames, ","), fmt.Sprintf("comma-separated shrinkers applied in order to make failing inputs of stress/shrink commands smaller. some of %s, or '%s'. '%s' is added first by default if -spec is given.", strings.Join(atcoder.ShrinkerNames, ", "), shrinkNone, atcoder.ShrinkSpec))
+	flags.IntVar(&shrinkRuns, "shrinkruns", 1000, "max number of runs of your program for shrinking a failing input. unlimited if 0.")
+	flags.StringVar(&validator, "validator", "", "command which reads an input and exits with non-zero code if it is invalid. inputs rejected by it are not tried while shrinking. e.g.) 'python validate.py'")
+	flags.StringVar(&colorMode, "color", atcoder.ColorAuto, fmt.Sprintf("when to color outputs. one of %s. 'auto' colors them only on terminals.", strings.Join(atcoder.ColorModes, ", ")))
 	if err := flags.Parse(args[1:]); err != nil {
 		return nil, errors.New("failed to parse flags")
 	}
+	rest := flags.Args()
+	if positionalArg == "" && len(rest) > 0 {
+		positionalArg, rest = rest[0], rest[1:]
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	var unavailable []string
+	flags.Visit(func(f *flag.Flag) {
+		if !available[f.Name] {
+			unavailable = append(unavailable, "-"+f.Name)
+		}
+	})
+	if len(unavailable) > 0 {
+		return nil, fmt.Errorf("%s cannot be used with %s command", strings.Join(unavailable, ", "), subcommand)
+	}
 
-	if problemURL == "" {
+	if subcommand == commandNew {
+		contest = firstNonEmpty(contest, positionalArg)
+	} else if file == "" {
+		file = positionalArg
+	}
+
+	var cacheDirPath string
+	home, err := homedir.Dir()
+	if err != nil {
+		cacheDirPath = ""
+	} else {
+		cacheDirPath = path.Join(home, ".atctest")
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		workDir = ""
+	}
+	cfg, err := loadConfigs(cacheDirPath, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// options on the command line take precedence over the project config, the global config and the defaults in this order
+	setFlags := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	applyConfig := func(name string, dst *string, value string) {
+		if !setFlags[name] && value != "" {
+			*dst = value
+		}
+	}
+	// commands in the config are not used if the source file is given, whose preset selects the commands
+	if file == "" {
+		applyConfig("command", &command, cfg.Command)
+		applyConfig("build", &build, cfg.Build)
+	}
+	applyConfig("judge", &judgeName, cfg.Judge)
+	applyConfig("color", &colorMode, cfg.Color)
+	applyConfig("pattern", &pattern, cfg.Pattern)
+	applyConfig("layout", &layout, cfg.Layout)
+	if !setFlags["nocache"] && cfg.NoCache != nil {
+		nocache = *cfg.NoCache
+	}
+
+	if absError < 0 || relError < 0 {
+		return nil, errors.New("allowed error should not be negative")
+	}
+	if timeLimit < 0 {
+		return nil, errors.New("time limit should not be negative")
+	}
+	if memoryMB < 0 {
+		return nil, errors.New("memory limit should not be negative")
+	}
+	if parallel <= 0 {
+		return nil, errors.New("number of parallel runs should be positive")
+	}
+	if !atcoder.IsDiffMode(diffMode) {
+		return nil, fmt.Errorf("diff mode should be one of %s", strings.Join(atcoder.DiffModes, ", "))
+	}
+	if maxLines < 0 {
+		return nil, errors.New("max number of lines should not be negative")
+	}
+	if !atcoder.IsFormat(format) {
+		return nil, fmt.Errorf("format should be one of %s", strings.Join(atcoder.FormatNames, ", "))
+	}
+	if !atcoder.IsSampleLayout(layout) {
+		return nil, fmt.Errorf("layout should be one of %s", strings.Join(atcoder.SampleLayouts, ", "))
+	}
+	if !atcoder.IsColorMode(colorMode) {
+		return nil, fmt.Errorf("color should be one of %s", strings.Join(atcoder.ColorModes, ", "))
+	}
+	tolerance := atcoder.Tolerance{Absolute: absError, Relative: relError}
+
+	needsProblem := subcommand == commandTest || subcommand == commandSubmit || subcommand == commandDownload
+	if needsProblem && interactor == "" && problemURL == "" && (contest == "" || problem == "") {
+		patterns := defaultPathPatterns
+		if pattern != "" {
+			patterns = []string{pattern}
+		}
+		inferredContest, inferredProblem, ok, err := inferProblem(patterns, file, workDir)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			contest = firstNonEmpty(contest, inferredContest)
+			problem = firstNonEmpty(problem, inferredProblem)
+		}
+	}
+
+	if subcommand == commandLogin || subcommand == commandLogout {
+		// username and password are resolved later, or asked interactively on login
+	} else if subcommand == commandStress {
+		if command == "" && file == "" {
+			flags.Usage()
+			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
+		}
+		if (generator == "" && specText == "") || reference == "" {
+			return nil, errors.New("specify the generator and the naive solution. e.g.) -gen 'python gen.py' -brute 'python brute.py'")
+		}
+		if generator != "" && specText != "" {
+			return nil, errors.New("specify either the generator or the spec of inputs")
+		}
+		if iterations <= 0 {
+			return nil, errors.New("number of iterations should be positive")
+		}
+	} else if subcommand == commandShrink {
+		if command == "" && file == "" {
+			flags.Usage()
+			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
+		}
+		if caseInput == "" || reference == "" {
+			return nil, errors.New("specify the failing input and the naive solution. e.g.) -in in.txt -brute 'python brute.py'")
+		}
+	} else if subcommand == commandGen {
+		if specText == "" {
+			return nil, errors.New("specify the spec of random inputs. e.g.) -spec 'N int [1,10]; A [N]int [1,100]'")
+		}
+	} else if subcommand == commandAddCase {
+		if caseInput == "" || caseOutput == "" {
+			return nil, errors.New("specify the input and the expected output of the test case. e.g.) -in in.txt -out out.txt")
+		}
+	} else if subcommand == commandNew {
+		if contest == "" {
+			return nil, errors.New("specify the contest to prepare. e.g.) atctest new ABC051")
+		}
+	} else if interactor != "" {
+		if command == "" && file == "" {
+			flags.Usage()
+			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
+		}
+		if trials <= 0 {
+			return nil, errors.New("number of trials should be positive")
+		}
+		if format != atcoder.FormatHuman {
+			return nil, errors.New("interactive problems support only 'human' format")
+		}
+	} else if problemURL == "" {
 		if contest == "" {
 			flags.Usage()
 			return nil, fmt.Errorf("specify the contest you are challenging. e.g.) ABC051\n\n%s", errBuff.String())
@@ -72,13 +410,23 @@ func New(args []string, outStream, errStream io.Writer) (*App, error) {
 			flags.Usage()
 			return nil, errors.New("specify the problem you are solving. e.g.) C")
 		}
-		if command == "" {
+		if command == "" && file == "" && subcommand != commandDownload {
 			flags.Usage()
 			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
 		}
 	}
 
+	if subcommand == commandSubmit {
+		if file == "" {
+			return nil, errors.New("specify the source file to submit. e.g.) -file c.cpp")
+		}
+		if problemURL == "" && (contest == "" || problem == "") {
+			return nil, errors.New("specify the contest and the problem to submit. e.g.) -contest ABC051 -problem C")
+		}
+	}
+
 	problemURL = strings.Trim(problemURL, "'\"")
+	submitLang = strings.Trim(submitLang, "'\"")
 
 	var contestURL string
 	if problemURL == "" {
@@ -92,84 +440,663 @@ func New(args []string, outStream, errStream io.Writer) (*App, error) {
 	}
 
 	useCache := !nocache
-	var cacheDirPath string
-	home, err := homedir.Dir()
-	if err != nil {
-		cacheDirPath = ""
-	} else {
-		cacheDirPath = path.Join(home, ".atctest")
+	if password != "" {
+		_, _ = fmt.Fprintf(errStream, "[WARNING] -password is left in your shell history. consider %s, the config file or 'atctest login' instead\n", passwordEnvName)
+	}
+	username, password = resolveCredentials(os.Getenv, cfg, username, password)
+
+	if subcommand == commandNew && template == "" && (language != "" || cfg.Language != "") {
+		p, err := selectPreset("", language, cfg)
+		if err != nil {
+			return nil, err
+		}
+		template = p.Template
+	}
+	if dir == "" {
+		switch subcommand {
+		case commandNew:
+			dir = "."
+		case commandDownload:
+			dir = atcoder.DefaultSampleDir(layout)
+		}
+	}
+	if shrinkRuns < 0 {
+		return nil, errors.New("max number of runs for shrinking should not be negative")
 	}
+	var spec *atcoder.Spec
+	if specText != "" {
+		// the spec is read from the file if it exists
+		if content, err := ioutil.ReadFile(specText); err == nil {
+			specText = string(content)
+		}
+		if spec, err = atcoder.ParseSpec(specText); err != nil {
+			return nil, err
+		}
+	}
+
+	// inputs are shrunk following the spec first if it is given
+	if spec != nil && !setFlags["shrink"] {
+		shrink = strings.Join(concat([]string{atcoder.ShrinkSpec}, atcoder.DefaultShrinkerNames), ",")
+	}
+	var shrinkers []atcoder.Shrinker
+	if shrink != shrinkNone {
+		for _, name := range strings.Split(shrink, ",") {
+			shrinker, err := atcoder.NewShrinker(strings.TrimSpace(name), spec)
+			if err != nil {
+				return nil, err
+			}
+			shrinkers = append(shrinkers, shrinker)
+		}
+	}
+	if subcommand == commandShrink && len(shrinkers) == 0 {
+		return nil, errors.New("specify at least one shrinker for shrink command")
+	}
+
+	if testsDir == "" {
+		testsDir = localCaseDirName
+		if file != "" {
+			testsDir = filepath.Join(filepath.Dir(file), localCaseDirName)
+		}
+	}
+	if template != "" {
+		if template, err = homedir.Expand(template); err != nil {
+			return nil, err
+		}
+	}
+
+	var preset *Preset
+	if file != "" {
+		p, err := selectPreset(file, language, cfg)
+		if err != nil {
+			return nil, err
+		}
+		preset = &p
+		// the preset may be defined only with the template in the config
+		needsRun := subcommand == commandTest || subcommand == commandStress || subcommand == commandShrink || (subcommand == commandSubmit && !noCheck)
+		if needsRun && command == "" && preset.Run == "" {
+			return nil, fmt.Errorf("no command to run '%s'. set run of the preset in the config file, or specify the command. e.g.) -command './a.out'", file)
+		}
+	}
+
 	client := atcoder.NewClient(baseURL, useCache, cacheDirPath, outStream, errStream)
 	if err != nil {
 		return nil, err
 	}
 
-	checker := atcoder.NewChecker(outStream, errStream)
+	// results in machine-readable formats are written to outStream alone so that they can be parsed by other tools
+	messageStream := outStream
+	if format != atcoder.FormatHuman {
+		messageStream = errStream
+	}
+	formatter, err := atcoder.NewFormatter(format, outStream, atcoder.DiffOption{Mode: diffMode, MaxLines: maxLines, Color: colorMode})
+	if err != nil {
+		return nil, err
+	}
+	checker := atcoder.NewChecker(rlimit, parallel, colorMode, formatter, messageStream, errStream)
 
 	return &App{
 		client:  client,
 		checker: checker,
 
+		subcommand: subcommand,
+
 		contest: contest,
 		problem: problem,
 		command: command,
+		build:   build,
+
+		file:   file,
+		preset: preset,
+
+		submitLanguage: submitLang,
+		noCheck:        noCheck,
+		noWait:         noWait,
+
+		template:     template,
+		dir:          dir,
+		sampleLayout: layout,
 
-		username: username,
-		password: password,
+		testsDir:   testsDir,
+		caseInput:  caseInput,
+		caseOutput: caseOutput,
+		caseName:   caseName,
+		force:      force,
+
+		stressOption: atcoder.StressOption{Generator: generator, Spec: spec, Reference: reference, Iterations: iterations, Seed: seed, Shrinkers: shrinkers, Validator: validator, MaxShrinkRuns: shrinkRuns},
+		shrinkOption: atcoder.ShrinkOption{Reference: reference, Shrinkers: shrinkers, Validator: validator, Spec: spec, MaxRuns: shrinkRuns},
+
+		username:        username,
+		password:        password,
+		sessionFilePath: atcoder.SessionFilePath(cacheDirPath),
+		prompt:          newTerminalPrompt(os.Stdin, errStream),
 
 		contestURL: contestURL,
 		problemURL: problemURL,
 
-		outStream: outStream,
+		judgeName: judgeName,
+		tolerance: tolerance,
+
+		timeLimit:   timeLimit,
+		memoryLimit: memoryMB << 20,
+
+		interactor: interactor,
+		trials:     trials,
+		queryLimit: queryLimit,
+
+		outStream: messageStream,
 		errStream: errStream,
 	}, nil
 }
 
 func (a *App) Run() error {
-	beingHeld, err := a.client.IsContestBeingHeld(a.contestURL)
+	switch a.subcommand {
+	case commandSubmit:
+		return a.submit()
+	case commandLogin:
+		return a.login()
+	case commandLogout:
+		return a.logout()
+	case commandNew:
+		return a.scaffold()
+	case commandDownload:
+		return a.download()
+	case commandAddCase:
+		return a.addCase()
+	case commandStress:
+		return a.stress()
+	case commandShrink:
+		return a.shrink()
+	case commandGen:
+		return a.gen()
+	default:
+		return a.test()
+	}
+}
+
+// test builds the program and checks it against the samples.
+func (a *App) test() error {
+	cleanup, err := a.buildProgram()
+	defer cleanup()
 	if err != nil {
 		return err
 	}
 
-	if beingHeld {
-		if err := a.client.LogIn(a.username, a.password); err != nil {
+	if a.interactor != "" {
+		timeLimit := a.timeLimit
+		if timeLimit == 0 {
+			timeLimit = defaultTimeLimit
+		}
+		if summary := a.checker.CheckInteractive(a.command, a.interactor, a.trials, a.queryLimit, timeLimit); !summary.Success() {
+			return &FailureError{Target: "trials", Summary: summary}
+		}
+		return nil
+	}
+
+	if err := a.logInIfBeingHeld(); err != nil {
+		return err
+	}
+
+	problemURL, err := a.getProblemURL()
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+
+	problem, err := a.client.GetProblem(problemURL)
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+
+	judge, err := a.newJudge(problem)
+	if err != nil {
+		return err
+	}
+
+	timeLimit, memoryLimit := a.limits(problem)
+
+	localCases, err := atcoder.ReadLocalCases(a.testsDir)
+	if err != nil {
+		return err
+	}
+	samples := append(append([]atcoder.Sample{}, problem.Samples...), localCases...)
+
+	if summary := a.checker.Check(a.command, samples, judge, timeLimit, memoryLimit); !summary.Success() {
+		return &FailureError{Target: "samples", Summary: summary}
+	}
+
+	return nil
+}
+
+// submit submits the source file after checking the samples unless a.noCheck is set.
+func (a *App) submit() error {
+	if !a.noCheck {
+		if err := a.test(); err != nil {
 			return err
-		} else {
-			fmt.Println("login success")
 		}
 	}
 
-	var problemURL string
-	if a.problemURL != "" {
-		problemURL = a.problemURL
-	} else {
-		var err error
-		problemURL, err = a.client.GetProblemURL(a.contest, a.problem)
+	sourceCode, err := ioutil.ReadFile(a.file)
+	if err != nil {
+		return fmt.Errorf("failed to read the source file: %s", err)
+	}
+
+	language := a.submitLanguage
+	if language == "" && a.preset != nil {
+		language = a.preset.Submit
+	}
+
+	if err := a.logIn(); err != nil {
+		return &NetworkError{Err: err}
+	}
+	problemURL, err := a.getProblemURL()
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+
+	submissionURL, err := a.client.Submit(a.contestURL, problemURL, language, string(sourceCode))
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	_, _ = fmt.Fprintf(a.outStream, "submitted: %s\n", submissionURL)
+	if a.noWait {
+		return nil
+	}
+
+	status, err := a.client.WatchSubmission(submissionURL, submissionPollInterval, func(status *atcoder.SubmissionStatus) {
+		_, _ = fmt.Fprintf(a.outStream, "judge: %s\n", status)
+	})
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	if verdict := status.Verdict(); verdict != atcoder.VerdictSuccess {
+		return &FailureError{Target: "submission", Summary: atcoder.Summary{verdict: 1}}
+	}
+
+	return nil
+}
+
+// buildProgram selects the commands by the preset and builds the program.
+// the returned function removes the build artifacts, which should be called even if an error is returned.
+func (a *App) buildProgram() (func(), error) {
+	cleanup := func() {}
+	if a.preset != nil {
+		tmpDirPath, err := ioutil.TempDir("", "atctest")
 		if err != nil {
-			return err
+			return cleanup, err
+		}
+		cleanup = func() { _ = os.RemoveAll(tmpDirPath) }
+
+		preset := a.preset.expand(a.file, tmpDirPath)
+		if a.command == "" {
+			a.command = preset.Run
 		}
+		if a.build == "" {
+			a.build = preset.Build
+		}
+	}
+
+	if a.build != "" {
+		if success := a.checker.Build(a.build); !success {
+			return cleanup, &FailureError{Target: "build", Summary: atcoder.Summary{atcoder.VerdictCE: 1}}
+		}
+	}
+	return cleanup, nil
+}
+
+// stress compares your program with the naive solution on random inputs,
+// and saves the first input on which they differ as a local test case.
+func (a *App) stress() error {
+	cleanup, err := a.buildProgram()
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	judge, err := a.newJudge(&atcoder.Problem{})
+	if err != nil {
+		return err
+	}
+	option := a.stressOption
+	option.TimeLimit = a.timeLimit
+	if option.TimeLimit == 0 {
+		option.TimeLimit = defaultTimeLimit
+	}
+	option.MemoryLimit = a.memoryLimit
+
+	result, err := a.checker.Stress(a.command, option, judge)
+	if err != nil {
+		return err
+	}
+	if result.Counterexample == nil {
+		return nil
+	}
+
+	name, err := atcoder.AddLocalCase(a.testsDir, *result.Counterexample, a.force)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(a.outStream, "the input is saved as test case '%s' in %s\n", name, a.testsDir)
+	return &FailureError{Target: "random inputs", Summary: result.Summary}
+}
+
+// shrink makes the input on which your program fails against the naive solution smaller, and saves it as a local test case.
+func (a *App) shrink() error {
+	input, err := ioutil.ReadFile(a.caseInput)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %s", err)
+	}
+
+	cleanup, err := a.buildProgram()
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	judge, err := a.newJudge(&atcoder.Problem{})
+	if err != nil {
+		return err
+	}
+	option := a.shrinkOption
+	option.TimeLimit = a.timeLimit
+	if option.TimeLimit == 0 {
+		option.TimeLimit = defaultTimeLimit
+	}
+	option.MemoryLimit = a.memoryLimit
+
+	name := a.caseName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(a.caseInput), filepath.Ext(a.caseInput)) + "_shrunk"
+	}
+	// checked before shrinking so that the shrunk input is not thrown away
+	if !a.force && atcoder.LocalCaseExists(a.testsDir, name) {
+		return fmt.Errorf("test case '%s' already exists in %s. use -force to overwrite it", name, a.testsDir)
+	}
+	sample, result, err := a.checker.ShrinkInput(a.command, atcoder.Sample{Input: string(input), Name: name}, judge, option)
+	if err != nil {
+		return err
+	}
+	if sample == nil {
+		return nil
+	}
+
+	if name, err = atcoder.AddLocalCase(a.testsDir, *sample, a.force); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(a.outStream, "the input is saved as test case '%s' in %s\n", name, a.testsDir)
+	return &FailureError{Target: "inputs", Summary: atcoder.Summary{result.Verdict: 1}}
+}
+
+// gen prints the random input generated from the spec.
+func (a *App) gen() error {
+	input, err := a.stressOption.Spec.Generate(a.stressOption.Seed)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(a.outStream, input)
+	return nil
+}
+
+// addCase adds the test case to the directory of local test cases.
+func (a *App) addCase() error {
+	input, err := ioutil.ReadFile(a.caseInput)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %s", err)
+	}
+	output, err := ioutil.ReadFile(a.caseOutput)
+	if err != nil {
+		return fmt.Errorf("failed to read expected output: %s", err)
 	}
 
-	samples, err := a.client.GetSamples(problemURL)
+	name, err := atcoder.AddLocalCase(a.testsDir, atcoder.Sample{Input: string(input), Output: string(output), Name: a.caseName}, a.force)
 	if err != nil {
 		return err
 	}
+	_, _ = fmt.Fprintf(a.outStream, "added test case '%s' to %s\n", name, a.testsDir)
+	return nil
+}
+
+// limits returns the time and memory limits given by the options, or those of the problem.
+// it is warned if the memory limit is unknown, because memory usage is not checked then.
+func (a *App) limits(problem *atcoder.Problem) (time.Duration, int64) {
+	timeLimit := a.timeLimit
+	if timeLimit == 0 {
+		timeLimit = problem.TimeLimit
+	}
+	if timeLimit == 0 {
+		timeLimit = defaultTimeLimit
+	}
+
+	memoryLimit := a.memoryLimit
+	if memoryLimit == 0 {
+		memoryLimit = problem.MemoryLimit
+	}
+	if memoryLimit == 0 {
+		_, _ = fmt.Fprintln(a.errStream, "[WARNING] memory limit of the problem is unknown, so memory usage is not checked. specify it by -memorylimit")
+	}
+	return timeLimit, memoryLimit
+}
+
+// logInIfBeingHeld logs in because problems of the contest being held are shown only to logged-in users.
+func (a *App) logInIfBeingHeld() error {
+	beingHeld, err := a.client.IsContestBeingHeld(a.contestURL)
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	if beingHeld {
+		if err := a.logIn(); err != nil {
+			return &NetworkError{Err: err}
+		}
+		_, _ = fmt.Fprintln(a.outStream, "login success")
+	}
+	return nil
+}
+
+// logIn logs in to AtCoder unless the stored session is available.
+// username and password are asked interactively if they are not resolved from the environment, config or options.
+func (a *App) logIn() error {
+	if (a.username == "" || a.password == "") && !a.client.IsLoggedIn(a.username) && a.prompt != nil {
+		username, password, err := a.prompt(a.username)
+		if err != nil {
+			return err
+		}
+		a.username, a.password = username, password
+	}
+	return a.client.LogIn(a.username, a.password)
+}
 
-	if success := a.checker.Check(a.command, samples); !success {
+// login logs in to AtCoder and stores the session so that following runs do not need username and password.
+func (a *App) login() error {
+	if err := a.logIn(); err != nil {
+		return &NetworkError{Err: err}
+	}
+	if err := a.client.SaveSession(); err != nil {
 		return err
 	}
+	_, _ = fmt.Fprintln(a.outStream, "login success. the session is stored in "+a.sessionFilePath)
+	return nil
+}
 
+func (a *App) logout() error {
+	if err := a.client.ClearSession(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(a.outStream, "logout success. the stored session is removed")
 	return nil
 }
 
+// getProblemURL returns the url of the problem page. it is remembered because each page can be visited only once.
+func (a *App) getProblemURL() (string, error) {
+	if a.problemURL != "" {
+		return a.problemURL, nil
+	}
+
+	problemURL, err := a.client.GetProblemURL(a.contest, a.problem)
+	if err != nil {
+		return "", err
+	}
+	a.problemURL = problemURL
+	return problemURL, nil
+}
+
+func (a *App) newJudge(problem *atcoder.Problem) (atcoder.Judge, error) {
+	if a.judgeName != "" && !atcoder.IsBuiltinJudge(a.judgeName) {
+		return atcoder.NewSpecialJudge(a.judgeName), nil
+	}
+
+	tolerance := a.tolerance
+	if tolerance.IsZero() {
+		if problem.Tolerance > 0 {
+			tolerance = atcoder.Tolerance{Absolute: problem.Tolerance, Relative: problem.Tolerance}
+		} else {
+			tolerance = defaultTolerance
+		}
+	}
+
+	judgeName := a.judgeName
+	if judgeName == "" {
+		if a.tolerance.IsZero() && problem.Tolerance == 0 {
+			judgeName = atcoder.JudgeExact
+		} else {
+			judgeName = atcoder.JudgeFloat
+		}
+	}
+	if judgeName == atcoder.JudgeFloat {
+		_, _ = fmt.Fprintf(a.outStream, "outputs are judged with %s\n", tolerance)
+	}
+
+	return atcoder.NewJudge(judgeName, tolerance)
+}
+
+// printDefaults prints usages of the available flags.
+func printDefaults(w io.Writer, flags *flag.FlagSet, available map[string]bool) {
+	usage := flag.NewFlagSet(flags.Name(), flag.ContinueOnError)
+	usage.SetOutput(w)
+	flags.VisitAll(func(f *flag.Flag) {
+		if available[f.Name] {
+			usage.Var(f.Value, f.Name, f.Usage)
+			usage.Lookup(f.Name).DefValue = f.DefValue
+		}
+	})
+	usage.PrintDefaults()
+}
+
+func concat(lists ...[]string) []string {
+	var concatenated []string
+	for _, list := range lists {
+		concatenated = append(concatenated, list...)
+	}
+	return concatenated
+}
+
+func isCommand(name string) bool {
+	for _, c := range commands {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isSourceFile reports whether the argument is a source file rather than a command. commands have neither extensions nor directories.
+func isSourceFile(arg string) bool {
+	if strings.ContainsAny(arg, "./"+string(os.PathSeparator)) {
+		return true
+	}
+	info, err := os.Stat(arg)
+	return err == nil && !info.IsDir()
+}
+
 const helpMessage = `atctest is a command line tool for AtCoder.
 it checks if your program correctly solve the samples provided on the problem page.
 
 EXAMPLE: 
 $ atctest -contest ABC051 -problem C -command 'python c.py'
-$ atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c' -command 'g++ c.cpp; ./a.out'
+$ atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c' -command 'python c.py'
+
+# for compiled languages, build command is executed once before running samples
+$ atctest -contest ABC051 -problem C -build 'g++ -O2 c.cpp' -command './a.out'
+
+# contest and problem are inferred from the path of the source file (or the working directory),
+# laid out as {contest}/{problem}.* or {contest}/{problem}/* by default
+$ atctest abc051/c.cpp
+$ atctest submit ABC051/C/main.rs
+$ atctest -pattern '{contest}/tasks/{problem}_*.py' arc100/tasks/c_solve.py
+
+# samples can be run concurrently
+$ atctest -contest ABC051 -problem C -command 'python c.py' -parallel 4
+
+# build/run commands are selected by the extension of the source file
+$ atctest -contest ABC051 -problem C -file c.cpp
+$ atctest -contest ABC051 -problem C -file c.py -lang pypy
+
+# allowed error of floating-point outputs is detected from the problem page, or can be specified explicitly
+$ atctest -contest ABC026 -problem D -command 'python d.py' -abserror 1e-6 -relerror 1e-6
+
+# outputs can be compared in other ways than exact match
+$ atctest -contest ABC051 -problem C -command 'python c.py' -judge whitespace
+
+# time limit of the problem is applied to each sample, or can be specified explicitly
+$ atctest -contest ABC051 -problem C -command 'python c.py' -timelimit 500ms
+
+# memory limit of the problem is checked against peak memory usage, and can be enforced by rlimit
+$ atctest -contest ABC051 -problem C -command './a.out' -memorylimit 256 -rlimit
+
+# difference of outputs is shown line by line, or side by side
+$ atctest -contest ABC051 -problem C -command 'python c.py' -diff side -maxlines 100
+
+# results can be written in machine-readable formats for editors and CI
+$ atctest -contest ABC051 -problem C -command 'python c.py' -format json
+$ atctest -contest ABC051 -problem C -command 'python c.py' -format junit > report.xml
+
+# for problems with multiple answers, your special judge program decides by its exit code.
+# it is executed with paths of input, your output and expected output as arguments
+$ atctest -contest ABC051 -problem C -command 'python c.py' -judge 'python judge.py'
+
+# for interactive problems, your program talks with the interactor via stdin/stdout
+$ atctest -command 'python b.py' -interactor 'python interactor.py' -trials 5 -querylimit 100
+
+# submit your code after checking samples, and wait for the judge result.
+# the language is selected by the preset of the source file, or can be specified explicitly
+$ atctest submit -contest ABC051 -problem C -file c.cpp
+$ atctest submit -contest ABC051 -problem C -file c.py -submitlang 'PyPy3' -nocheck -nowait
+
+# directories of all problems are created with samples and the template of the language
+$ atctest new ABC051 -lang cpp
+$ atctest new ABC051 -template ~/template.py -dir contests
+
+# samples are written into files, which can be used by other tools such as online-judge-tools
+$ atctest download -contest ABC051 -problem C
+$ atctest download -contest ABC051 -problem C -layout oj -dir test
+
+# your own test cases in tests/ ({name}.in and {name}.out) are checked with samples
+$ atctest add-case c.py -in in.txt -out out.txt -name max
+$ atctest c.py
+
+# your program is compared with the naive solution on random inputs from the generator,
+# and the first input on which they differ is saved in tests/
+$ atctest stress c.cpp -gen 'python gen.py' -brute 'python brute.py' -iterations 1000
+
+# random inputs can be generated from the spec of the input format without writing the generator
+$ atctest gen -spec 'N int [1,2e5]; A [N]int [1,1e9]; edges tree N' -seed 3
+$ atctest stress c.cpp -spec spec.txt -brute 'python brute.py'
+
+# the failing input found by stress command is shrunk by removing lines and tokens and making numbers smaller.
+# an input can also be shrunk by shrink command
+$ atctest shrink c.cpp -in big.txt -brute 'python brute.py' -shrink lines,tokens
+# inputs are shrunk following the spec of inputs if it is given
+$ atctest shrink c.cpp -in big.txt -brute 'python brute.py' -spec spec.txt
+
+# login session can be stored so that username and password are not required for following runs
+$ atctest login
+$ atctest logout
+
+# for contest in session, login is required to test your code.
+# username and password are read from -username/-password options, ATCTEST_USERNAME/ATCTEST_PASSWORD or the config file, or asked interactively.
+# the environment variables or the config file are recommended because options are left in your shell history
+$ ATCTEST_USERNAME=mui87 atctest -contest ABC127 -problem B -command 'ruby b.rb'
 
-# for contest in session, login is required to test your code
-$ atctest -contest ABC127 -problem B -command 'ruby b.rb' -username mui87 -password pass1234
+# defaults of options can be written in ~/.atctest/config.toml, and overridden by .atctest.toml of your project,
+# which is searched from the current directory up to the root. options on the command line take precedence
+$ echo 'command = "python c.py"' > .atctest.toml
+$ atctest -contest ABC051 -problem C
 
 OPTION:`
@@ -2,8 +2,13 @@ package app
 
 import (
 	"bytes"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/mui87/atctest/atcoder"
 )
 
 func TestNew(t *testing.T) {
@@ -15,39 +20,304 @@ func TestNew(t *testing.T) {
 	}{
 		{
 			name:               "success",
-			inputArgs:          strings.Fields("atctest -contest ABC051 -problem C -command 'python c.py'"),
+			inputArgs:          splitArgs("atctest -contest ABC051 -problem C -command 'python c.py'"),
 			expectedContestURL: "https://atcoder.jp/contests/abc051",
 		},
 		{
 			name:               "success-with url_old",
-			inputArgs:          strings.Fields("atctest -url 'https://abc051.contest.atcoder.jp/tasks/abc051_c'"),
+			inputArgs:          splitArgs("atctest -url 'https://abc051.contest.atcoder.jp/tasks/abc051_c'"),
 			expectedContestURL: "https://abc051.contest.atcoder.jp",
 		},
 		{
 			name:               "success-with url_new",
-			inputArgs:          strings.Fields("atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c'"),
+			inputArgs:          splitArgs("atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c'"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-with judge",
+			inputArgs:          splitArgs("atctest -contest ABC051 -problem C -judge whitespace -command 'python c.py'"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-with special judge",
+			inputArgs:          splitArgs("atctest -contest ABC051 -problem C -judge ./judge -command 'python c.py'"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-interactive",
+			inputArgs:          splitArgs("atctest -interactor ./interactor -command ./a.out"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-with file",
+			inputArgs:          splitArgs("atctest -contest ABC051 -problem C -file c.cpp"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-contest and problem inferred from file",
+			inputArgs:          splitArgs("atctest abc051/c.py -lang pypy"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-submit with inferred problem",
+			inputArgs:          splitArgs("atctest submit ABC051/C/main.rs -nocheck"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-contest flag takes precedence over file",
+			inputArgs:          splitArgs("atctest abc051/c.py -contest ARC100"),
+			expectedContestURL: "https://atcoder.jp/contests/arc100",
+		},
+		{
+			name:               "success-with diff mode",
+			inputArgs:          splitArgs("atctest -contest ABC051 -problem C -diff side -maxlines 10 -command 'python c.py'"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-with format",
+			inputArgs:          splitArgs("atctest -contest ABC051 -problem C -format json -command 'python c.py'"),
 			expectedContestURL: "https://atcoder.jp/contests/abc051",
 		},
+		{
+			name:               "success-submit",
+			inputArgs:          splitArgs("atctest submit -contest ABC051 -problem C -file c.cpp"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-submit with url",
+			inputArgs:          splitArgs("atctest submit -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c' -file c.py -submitlang 4047 -nocheck -nowait"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-login",
+			inputArgs:          splitArgs("atctest login -username chokudai -password password"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-new",
+			inputArgs:          splitArgs("atctest new ABC051 -lang cpp -dir contests"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-download",
+			inputArgs:          splitArgs("atctest download -contest ABC051 -problem C -layout oj"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-download with inferred problem",
+			inputArgs:          splitArgs("atctest download abc051/c.py -dir samples"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-add-case",
+			inputArgs:          splitArgs("atctest add-case c.py -in in.txt -out out.txt -name max"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-add-case overwriting",
+			inputArgs:          splitArgs("atctest add-case c.py -in in.txt -out out.txt -name max -force"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-stress",
+			inputArgs:          splitArgs("atctest stress c.cpp -gen ./gen -brute ./brute -iterations 1000 -seed 42"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-shrink",
+			inputArgs:          splitArgs("atctest shrink c.cpp -in big.txt -brute ./brute -shrink lines,tokens -shrinkruns 100"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-shrink with validator",
+			inputArgs:          splitArgs("atctest shrink c.cpp -in big.txt -brute ./brute -validator './validate'"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-stress without shrinking",
+			inputArgs:          splitArgs("atctest stress c.cpp -gen ./gen -brute ./brute -shrink none"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-gen",
+			inputArgs:          splitArgs("atctest gen -spec testdata/spec.txt -seed 3"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-stress with spec",
+			inputArgs:          splitArgs("atctest stress c.cpp -spec testdata/spec.txt -brute ./brute"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:               "success-file after flags",
+			inputArgs:          splitArgs("atctest -pattern '{contest}/tasks/{problem}_*.py' arc100/tasks/c_solve.py"),
+			expectedContestURL: "https://atcoder.jp/contests/arc100",
+		},
+		{
+			name:               "success-file after flags of command",
+			inputArgs:          splitArgs("atctest submit -contest ABC051 -problem C c.py"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-logout",
+			inputArgs:          splitArgs("atctest logout"),
+			expectedContestURL: "https://atcoder.jp/contests/",
+		},
+		{
+			name:           "failure-invalid color",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C -color rainbow -command 'python c.py'"),
+			expectedErrMsg: "color should be one of",
+		},
+		{
+			name:           "failure-new without contest",
+			inputArgs:      splitArgs("atctest new -lang cpp"),
+			expectedErrMsg: "specify the contest to prepare",
+		},
+		{
+			name:           "failure-new with unknown language",
+			inputArgs:      splitArgs("atctest new ABC051 -lang cobol"),
+			expectedErrMsg: "unknown language 'cobol'",
+		},
+		{
+			name:           "failure-download without problem",
+			inputArgs:      splitArgs("atctest download -contest ABC051"),
+			expectedErrMsg: "specify the problem",
+		},
+		{
+			name:           "failure-invalid layout",
+			inputArgs:      splitArgs("atctest download -contest ABC051 -problem C -layout zip"),
+			expectedErrMsg: "layout should be one of",
+		},
+		{
+			name:           "failure-add-case without output",
+			inputArgs:      splitArgs("atctest add-case -in in.txt"),
+			expectedErrMsg: "specify the input and the expected output",
+		},
+		{
+			name:           "failure-stress without naive solution",
+			inputArgs:      splitArgs("atctest stress c.cpp -gen ./gen"),
+			expectedErrMsg: "specify the generator and the naive solution",
+		},
+		{
+			name:           "failure-gen without spec",
+			inputArgs:      splitArgs("atctest gen -seed 3"),
+			expectedErrMsg: "specify the spec of random inputs",
+		},
+		{
+			name:           "failure-invalid spec",
+			inputArgs:      splitArgs("atctest gen -spec Nint"),
+			expectedErrMsg: "statement should be like",
+		},
+		{
+			name:           "failure-stress with generator and spec",
+			inputArgs:      splitArgs("atctest stress c.cpp -gen ./gen -spec testdata/spec.txt -brute ./brute"),
+			expectedErrMsg: "specify either the generator or the spec of inputs",
+		},
+		{
+			name:           "failure-flag of other command",
+			inputArgs:      splitArgs("atctest gen -spec testdata/spec.txt -parallel 4"),
+			expectedErrMsg: "-parallel cannot be used with gen command",
+		},
+		{
+			name:           "failure-force with gen command",
+			inputArgs:      splitArgs("atctest gen -spec testdata/spec.txt -force"),
+			expectedErrMsg: "-force cannot be used with gen command",
+		},
+		{
+			name:           "failure-flags of other commands",
+			inputArgs:      splitArgs("atctest logout -pattern x -nocache"),
+			expectedErrMsg: "-nocache, -pattern cannot be used with logout command",
+		},
+		{
+			name:           "failure-too many arguments",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C a.py b.py"),
+			expectedErrMsg: "unexpected arguments: b.py",
+		},
+		{
+			name:           "failure-file before and after flags",
+			inputArgs:      splitArgs("atctest a.py -contest ABC051 -problem C b.py"),
+			expectedErrMsg: "unexpected arguments: b.py",
+		},
+		{
+			name:           "failure-unknown shrinker",
+			inputArgs:      splitArgs("atctest stress c.cpp -gen ./gen -brute ./brute -shrink lines,bytes"),
+			expectedErrMsg: "unknown shrinker 'bytes'",
+		},
+		{
+			name:           "failure-spec shrinker without spec",
+			inputArgs:      splitArgs("atctest shrink c.cpp -in big.txt -brute ./brute -shrink spec,lines"),
+			expectedErrMsg: "shrinker 'spec' needs the spec of inputs",
+		},
+		{
+			name:           "failure-shrink without input",
+			inputArgs:      splitArgs("atctest shrink c.cpp -brute ./brute"),
+			expectedErrMsg: "specify the failing input and the naive solution",
+		},
+		{
+			name:           "failure-unknown command",
+			inputArgs:      splitArgs("atctest hello -contest ABC051 -problem C -command 'python c.py'"),
+			expectedErrMsg: "unknown command 'hello'",
+		},
+		{
+			name:           "failure-submit without file",
+			inputArgs:      splitArgs("atctest submit -contest ABC051 -problem C -command 'python c.py'"),
+			expectedErrMsg: "specify the source file to submit",
+		},
 		{
 			name:           "failure-unknown option exists",
-			inputArgs:      strings.Fields("atctest -hello world -problem C -command 'python c.py'"),
+			inputArgs:      splitArgs("atctest -hello world -problem C -command 'python c.py'"),
 			expectedErrMsg: "failed to parse flags",
 		},
 		{
 			name:           "failure-contest option missing",
-			inputArgs:      strings.Fields("atctest -problem C -command 'python c.py'"),
+			inputArgs:      splitArgs("atctest -problem C -command 'python c.py'"),
 			expectedErrMsg: "specify the contest",
 		},
 		{
 			name:           "failure-problem option missing",
-			inputArgs:      strings.Fields("atctest -contest ABC051 -command 'python c.py'"),
+			inputArgs:      splitArgs("atctest -contest ABC051 -command 'python c.py'"),
 			expectedErrMsg: "specify the problem",
 		},
 		{
 			name:           "failure-command option missing",
-			inputArgs:      strings.Fields("atctest -contest ABC051 -problem C"),
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C"),
+			expectedErrMsg: "specify the command",
+		},
+		{
+			name:           "failure-negative memory limit",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C -memorylimit -1 -command 'python c.py'"),
+			expectedErrMsg: "memory limit should not be negative",
+		},
+		{
+			name:           "failure-unknown language",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C -file c.cob"),
+			expectedErrMsg: "could not detect the language",
+		},
+		{
+			name:           "failure-interactive command option missing",
+			inputArgs:      splitArgs("atctest -interactor ./interactor"),
 			expectedErrMsg: "specify the command",
 		},
+		{
+			name:           "failure-negative error",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C -abserror -1 -command 'python c.py'"),
+			expectedErrMsg: "should not be negative",
+		},
+		{
+			name:           "failure-unknown diff mode",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C -diff color -command 'python c.py'"),
+			expectedErrMsg: "diff mode should be one of",
+		},
+		{
+			name:           "failure-unknown format",
+			inputArgs:      splitArgs("atctest -contest ABC051 -problem C -format xml -command 'python c.py'"),
+			expectedErrMsg: "format should be one of",
+		},
+		{
+			name:           "failure-interactive with format",
+			inputArgs:      splitArgs("atctest -interactor ./interactor -format json -command ./a.out"),
+			expectedErrMsg: "support only 'human' format",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -71,3 +341,212 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestNew_quotedCommands(t *testing.T) {
+	// quotes in commands are passed to the shell as they are
+	tests := []struct {
+		name            string
+		inputArgs       []string
+		actual          func(a *App) string
+		expectedCommand string
+	}{
+		{
+			name:            "generator",
+			inputArgs:       splitArgs(`atctest stress c.cpp -gen 'python3 -c "print(3)"' -brute ./brute`),
+			actual:          func(a *App) string { return a.stressOption.Generator },
+			expectedCommand: `python3 -c "print(3)"`,
+		},
+		{
+			name:            "naive solution",
+			inputArgs:       splitArgs(`atctest stress c.cpp -gen ./gen -brute 'python3 -c "print(int(input())*2)"'`),
+			actual:          func(a *App) string { return a.stressOption.Reference },
+			expectedCommand: `python3 -c "print(int(input())*2)"`,
+		},
+		{
+			name:            "special judge",
+			inputArgs:       splitArgs(`atctest -contest ABC051 -problem C -command ./a.out -judge 'python3 -c "import judge"'`),
+			actual:          func(a *App) string { return a.judgeName },
+			expectedCommand: `python3 -c "import judge"`,
+		},
+		{
+			name:            "interactor",
+			inputArgs:       splitArgs(`atctest -command ./a.out -interactor 'python3 -c "import interactor"'`),
+			actual:          func(a *App) string { return a.interactor },
+			expectedCommand: `python3 -c "import interactor"`,
+		},
+		{
+			name:            "build",
+			inputArgs:       splitArgs(`atctest -contest ABC051 -problem C -command ./a.out -build 'sh -c "g++ -O2 -o a.out c.cpp"'`),
+			actual:          func(a *App) string { return a.build },
+			expectedCommand: `sh -c "g++ -O2 -o a.out c.cpp"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if command := test.actual(a); command != test.expectedCommand {
+				t.Fatalf("command wrong. want=%s, got=%s", test.expectedCommand, command)
+			}
+		})
+	}
+}
+
+func TestApp_limits(t *testing.T) {
+	tests := []struct {
+		name                string
+		inputTimeLimit      time.Duration
+		inputMemoryLimit    int64
+		inputProblem        *atcoder.Problem
+		expectedTimeLimit   time.Duration
+		expectedMemoryLimit int64
+		expectedWarning     string
+	}{
+		{
+			name:                "limits of problem",
+			inputProblem:        &atcoder.Problem{TimeLimit: 3 * time.Second, MemoryLimit: 1024 << 20},
+			expectedTimeLimit:   3 * time.Second,
+			expectedMemoryLimit: 1024 << 20,
+		},
+		{
+			name:                "limits of options",
+			inputTimeLimit:      time.Second,
+			inputMemoryLimit:    256 << 20,
+			inputProblem:        &atcoder.Problem{TimeLimit: 3 * time.Second, MemoryLimit: 1024 << 20},
+			expectedTimeLimit:   time.Second,
+			expectedMemoryLimit: 256 << 20,
+		},
+		{
+			name:              "unknown limits",
+			inputProblem:      &atcoder.Problem{},
+			expectedTimeLimit: defaultTimeLimit,
+			expectedWarning:   "memory limit of the problem is unknown",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var errStream bytes.Buffer
+			a := &App{timeLimit: test.inputTimeLimit, memoryLimit: test.inputMemoryLimit, errStream: &errStream}
+			timeLimit, memoryLimit := a.limits(test.inputProblem)
+			if timeLimit != test.expectedTimeLimit {
+				t.Fatalf("time limit wrong. want=%s, got=%s", test.expectedTimeLimit, timeLimit)
+			}
+			if memoryLimit != test.expectedMemoryLimit {
+				t.Fatalf("memory limit wrong. want=%d, got=%d", test.expectedMemoryLimit, memoryLimit)
+			}
+			if test.expectedWarning == "" && errStream.Len() > 0 {
+				t.Fatalf("no warning is expected. got: %s", errStream.String())
+			}
+			if !strings.Contains(errStream.String(), test.expectedWarning) {
+				t.Fatalf("expect '%s' to contain '%s'", errStream.String(), test.expectedWarning)
+			}
+		})
+	}
+}
+
+func TestNew_shrinkers(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputArgs         []string
+		expectedShrinkers []string
+	}{
+		{
+			name:              "default",
+			inputArgs:         splitArgs("atctest shrink c.cpp -in big.txt -brute ./brute"),
+			expectedShrinkers: []string{"lines", "tokens", "numbers"},
+		},
+		{
+			name:              "default with spec",
+			inputArgs:         splitArgs("atctest shrink c.cpp -in big.txt -brute ./brute -spec testdata/spec.txt"),
+			expectedShrinkers: []string{"spec", "lines", "tokens", "numbers"},
+		},
+		{
+			name:              "selected with spec",
+			inputArgs:         splitArgs("atctest stress c.cpp -spec testdata/spec.txt -brute ./brute -shrink numbers"),
+			expectedShrinkers: []string{"numbers"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			var names []string
+			for _, shrinker := range a.shrinkOption.Shrinkers {
+				names = append(names, shrinker.Name())
+			}
+			if !reflect.DeepEqual(names, test.expectedShrinkers) {
+				t.Fatalf("shrinkers wrong. want=%v, got=%v", test.expectedShrinkers, names)
+			}
+		})
+	}
+}
+
+func TestNew_presetWithOnlyTemplate(t *testing.T) {
+	// the project config defines presets only with templates
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("testdata/template_only"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	var outStream, errStream bytes.Buffer
+	a, err := New(splitArgs("atctest stress a.py -spec 'N int [1,3]' -brute cat"), &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	expected := Preset{Run: "python3 {file}", Submit: "Python (", Template: "template.py"}
+	if *a.preset != expected {
+		t.Fatalf("preset wrong. want=%+v, got=%+v", expected, *a.preset)
+	}
+
+	_, err = New(splitArgs("atctest stress a.ml -spec 'N int [1,3]' -brute cat"), &outStream, &errStream)
+	if err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+	if expectedErrMsg := "no command to run 'a.ml'"; !strings.Contains(err.Error(), expectedErrMsg) {
+		t.Fatalf("expect '%s' to contain '%s'", err.Error(), expectedErrMsg)
+	}
+
+	if _, err = New(splitArgs("atctest stress a.ml -spec 'N int [1,3]' -brute cat -command ./a.out"), &outStream, &errStream); err != nil {
+		t.Fatalf("err should be nil with the command. got: %s", err)
+	}
+}
+
+// splitArgs splits the command line into arguments like shells. arguments can be quoted by single quotes.
+func splitArgs(commandLine string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quoted  bool
+		inArg   bool
+	)
+	for _, r := range commandLine {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			inArg = true
+		case r == ' ' && !quoted:
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
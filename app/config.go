@@ -0,0 +1,168 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mui87/atctest/atcoder"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	configFileName        = "config.toml"
+	projectConfigFileName = ".atctest.toml"
+)
+
+// the config file can be written in YAML instead of TOML. the first existing one is used.
+var (
+	configFileNames        = []string{configFileName, "config.yaml", "config.yml"}
+	projectConfigFileNames = []string{projectConfigFileName, ".atctest.yaml", ".atctest.yml"}
+)
+
+// config holds the settings of the config files. empty fields are not set and the defaults of options are used.
+type config struct {
+	Language   string            `toml:"language" yaml:"language"`
+	Command    string            `toml:"command" yaml:"command"`
+	Build      string            `toml:"build" yaml:"build"`
+	Judge      string            `toml:"judge" yaml:"judge"`
+	NoCache    *bool             `toml:"nocache" yaml:"nocache"`
+	Color      string            `toml:"color" yaml:"color"`
+	Pattern    string            `toml:"pattern" yaml:"pattern"`
+	Layout     string            `toml:"layout" yaml:"layout"`
+	Presets    map[string]Preset `toml:"presets" yaml:"presets"`
+	Extensions map[string]string `toml:"extensions" yaml:"extensions"`
+	Username   string            `toml:"username" yaml:"username"`
+	Password   string            `toml:"password" yaml:"password"`
+}
+
+// loadConfig reads the config file. empty config is returned if the file does not exist.
+// the file is decoded as YAML if its extension is .yaml or .yml, otherwise as TOML.
+func loadConfig(configFilePath string) (*config, error) {
+	var cfg config
+	if _, err := os.Stat(configFilePath); os.IsNotExist(err) {
+		return &cfg, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(configFilePath)) {
+	case ".yaml", ".yml":
+		bytes, err := ioutil.ReadFile(configFilePath)
+		if err == nil {
+			err = yaml.UnmarshalStrict(bytes, &cfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file '%s': %s", configFilePath, err)
+		}
+	default:
+		metadata, err := toml.DecodeFile(configFilePath, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file '%s': %s", configFilePath, err)
+		}
+		// unknown keys are rejected like yaml so that misspelled keys are not silently ignored
+		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return nil, fmt.Errorf("failed to load config file '%s': unknown keys %s", configFilePath, strings.Join(keys, ", "))
+		}
+	}
+	if cfg.Color != "" && !atcoder.IsColorMode(cfg.Color) {
+		return nil, fmt.Errorf("color in config file '%s' should be one of %s", configFilePath, strings.Join(atcoder.ColorModes, ", "))
+	}
+	return &cfg, nil
+}
+
+// loadConfigs reads the global config file in cacheDirPath and the project config file
+// found by walking up from workDir, and merges them. the project config takes precedence.
+// either path can be empty to skip the file.
+func loadConfigs(cacheDirPath, workDir string) (*config, error) {
+	cfg := &config{}
+	var paths []string
+	if cacheDirPath != "" {
+		if p := findFile(cacheDirPath, configFileNames); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if workDir != "" {
+		if p := findProjectConfig(workDir); p != "" {
+			paths = append(paths, p)
+		}
+	}
+
+	for _, p := range paths {
+		c, err := loadConfig(p)
+		if err != nil {
+			return nil, err
+		}
+		cfg.merge(c)
+	}
+	return cfg, nil
+}
+
+// findProjectConfig returns the path of the project config file in dir or its nearest ancestor.
+// empty string is returned if it is not found.
+func findProjectConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if p := findFile(dir, projectConfigFileNames); p != "" {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// merge overwrites the settings with those set in other. presets and extensions are merged by their keys,
+// and fields of presets are merged one by one like the presets over the built-in ones.
+func (c *config) merge(other *config) {
+	mergeString := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	mergeString(&c.Language, other.Language)
+	mergeString(&c.Command, other.Command)
+	mergeString(&c.Build, other.Build)
+	mergeString(&c.Judge, other.Judge)
+	mergeString(&c.Color, other.Color)
+	mergeString(&c.Pattern, other.Pattern)
+	mergeString(&c.Layout, other.Layout)
+	mergeString(&c.Username, other.Username)
+	mergeString(&c.Password, other.Password)
+	if other.NoCache != nil {
+		c.NoCache = other.NoCache
+	}
+
+	for name, preset := range other.Presets {
+		if c.Presets == nil {
+			c.Presets = map[string]Preset{}
+		}
+		c.Presets[name] = c.Presets[name].override(preset)
+	}
+	for ext, name := range other.Extensions {
+		if c.Extensions == nil {
+			c.Extensions = map[string]string{}
+		}
+		c.Extensions[ext] = name
+	}
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func Test_loadConfigs(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name           string
+		inputCacheDir  string
+		inputWorkDir   string
+		expectedConfig *config
+		expectedErrMsg string
+	}{
+		{
+			name:           "success-no config",
+			inputCacheDir:  "testdata/global/notexist",
+			inputWorkDir:   "",
+			expectedConfig: &config{},
+		},
+		{
+			name:          "success-global yaml config",
+			inputCacheDir: "testdata/global",
+			inputWorkDir:  "",
+			expectedConfig: &config{
+				Language: "pypy",
+				Command:  "python main.py",
+				Judge:    "whitespace",
+				NoCache:  &yes,
+				Color:    atcoder.ColorNever,
+				Username: "mui87",
+				Presets: map[string]Preset{
+					"cpp": {Build: "clang++ -std=c++17 -O2 -o {tmp}/a.out {file}", Run: "{tmp}/a.out"},
+				},
+				Extensions: map[string]string{".py": "pypy"},
+			},
+		},
+		{
+			name:          "success-project config found from subdirectory",
+			inputCacheDir: "",
+			inputWorkDir:  "testdata/project/abc051/c",
+			expectedConfig: &config{
+				Command: "./a.out",
+				Build:   "g++ -O2 main.cpp",
+				NoCache: &no,
+				Presets: map[string]Preset{
+					"cpp": {Build: "g++ -std=gnu++17 -O2 -o {tmp}/a.out {file}"},
+					"nim": {Build: "nim cpp -d:release -o:{tmp}/a.out {file}", Run: "{tmp}/a.out"},
+				},
+				Extensions: map[string]string{".nim": "nim"},
+			},
+		},
+		{
+			name:          "success-project config overrides global config",
+			inputCacheDir: "testdata/global",
+			inputWorkDir:  "testdata/project/abc051/c",
+			expectedConfig: &config{
+				Language: "pypy",
+				Command:  "./a.out",
+				Build:    "g++ -O2 main.cpp",
+				Judge:    "whitespace",
+				NoCache:  &no,
+				Color:    atcoder.ColorNever,
+				Username: "mui87",
+				Presets: map[string]Preset{
+					"cpp": {Build: "g++ -std=gnu++17 -O2 -o {tmp}/a.out {file}", Run: "{tmp}/a.out"},
+					"nim": {Build: "nim cpp -d:release -o:{tmp}/a.out {file}", Run: "{tmp}/a.out"},
+				},
+				Extensions: map[string]string{".py": "pypy", ".nim": "nim"},
+			},
+		},
+		{
+			name:           "failure-invalid color",
+			inputCacheDir:  "",
+			inputWorkDir:   "testdata/invalid",
+			expectedErrMsg: "color in config file",
+		},
+		{
+			name:           "failure-unknown keys",
+			inputCacheDir:  "",
+			inputWorkDir:   "testdata/misspelled",
+			expectedErrMsg: "unknown keys comand, presets.cpp.biuld",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := loadConfigs(test.inputCacheDir, test.inputWorkDir)
+
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if !reflect.DeepEqual(cfg, test.expectedConfig) {
+					t.Errorf("config is wrong.\nexpected: %+v\nactual:   %+v", test.expectedConfig, cfg)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("err should not be nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Errorf("err message should contain '%s'. got: %s", test.expectedErrMsg, err)
+				}
+			}
+		})
+	}
+}
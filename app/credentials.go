@@ -0,0 +1,67 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	usernameEnvName = "ATCTEST_USERNAME"
+	passwordEnvName = "ATCTEST_PASSWORD"
+)
+
+// resolveCredentials returns username and password from the command line options, the environment variables
+// and the config file in this order, as options on the command line take precedence over the config.
+func resolveCredentials(getenv func(string) string, cfg *config, flagUsername, flagPassword string) (string, string) {
+	return firstNonEmpty(flagUsername, getenv(usernameEnvName), cfg.Username),
+		firstNonEmpty(flagPassword, getenv(passwordEnvName), cfg.Password)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// credentialPrompt asks username and password interactively. username is asked only if it is empty.
+type credentialPrompt func(username string) (string, string, error)
+
+// newTerminalPrompt returns the prompt reading from the terminal without echoing the password.
+// nil is returned if stdin is not a terminal, e.g. in scripts.
+func newTerminalPrompt(in *os.File, out io.Writer) credentialPrompt {
+	fd := int(in.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil
+	}
+
+	return func(username string) (string, string, error) {
+		if username == "" {
+			_, _ = fmt.Fprint(out, "username: ")
+			line, err := bufio.NewReader(in).ReadString('\n')
+			if err != nil && line == "" {
+				return "", "", fmt.Errorf("failed to read username: %s", err)
+			}
+			username = strings.TrimSpace(line)
+		}
+
+		_, _ = fmt.Fprintf(out, "password for %s: ", username)
+		password, err := terminal.ReadPassword(fd)
+		_, _ = fmt.Fprintln(out)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read password: %s", err)
+		}
+		if username == "" || len(password) == 0 {
+			return "", "", errors.New("username and password should not be empty")
+		}
+		return username, string(password), nil
+	}
+}
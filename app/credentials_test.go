@@ -0,0 +1,59 @@
+package app
+
+import "testing"
+
+func Test_resolveCredentials(t *testing.T) {
+	tests := []struct {
+		name              string
+		inputEnv          map[string]string
+		inputConfig       *config
+		inputFlagUsername string
+		inputFlagPassword string
+		expectedUsername  string
+		expectedPassword  string
+	}{
+		{
+			name:              "options first",
+			inputEnv:          map[string]string{usernameEnvName: "env_user", passwordEnvName: "env_pass"},
+			inputConfig:       &config{Username: "config_user", Password: "config_pass"},
+			inputFlagUsername: "flag_user",
+			inputFlagPassword: "flag_pass",
+			expectedUsername:  "flag_user",
+			expectedPassword:  "flag_pass",
+		},
+		{
+			name:              "environment variables second",
+			inputEnv:          map[string]string{usernameEnvName: "env_user", passwordEnvName: "env_pass"},
+			inputConfig:       &config{Username: "config_user", Password: "config_pass"},
+			inputFlagUsername: "flag_user",
+			expectedUsername:  "flag_user",
+			expectedPassword:  "env_pass",
+		},
+		{
+			name:             "config file last",
+			inputEnv:         map[string]string{usernameEnvName: "env_user"},
+			inputConfig:      &config{Username: "config_user", Password: "config_pass"},
+			expectedUsername: "env_user",
+			expectedPassword: "config_pass",
+		},
+		{
+			name:             "not resolved",
+			inputEnv:         map[string]string{},
+			inputConfig:      &config{},
+			expectedUsername: "",
+			expectedPassword: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getenv := func(key string) string { return test.inputEnv[key] }
+			username, password := resolveCredentials(getenv, test.inputConfig, test.inputFlagUsername, test.inputFlagPassword)
+			if username != test.expectedUsername {
+				t.Fatalf("username wrong. want=%s, got=%s", test.expectedUsername, username)
+			}
+			if password != test.expectedPassword {
+				t.Fatalf("password wrong. want=%s, got=%s", test.expectedPassword, password)
+			}
+		})
+	}
+}
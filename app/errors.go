@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// FailureError is returned by App.Run when the program fails to build, does not pass all samples (or trials),
+// or the submission is not accepted.
+type FailureError struct {
+	Target  string // "build", "samples", "trials", "submission", "random inputs" or "inputs"
+	Summary atcoder.Summary
+}
+
+func (e *FailureError) Error() string {
+	if e.Summary[atcoder.VerdictCE] > 0 {
+		return "failed to build your program"
+	}
+	failed := e.Summary.Total() - e.Summary[atcoder.VerdictSuccess]
+	return fmt.Sprintf("%d of %d %s failed (%s)", failed, e.Summary.Total(), e.Target, e.Summary)
+}
+
+// Verdict returns the most severe verdict among failures, which decides the exit code.
+// the severity is in order of CE, ERROR, RE, MLE, TLE and FAILURE.
+func (e *FailureError) Verdict() atcoder.Verdict {
+	for _, v := range []atcoder.Verdict{atcoder.VerdictCE, atcoder.VerdictError, atcoder.VerdictRE, atcoder.VerdictMLE, atcoder.VerdictTLE, atcoder.VerdictFailure} {
+		if e.Summary[v] > 0 {
+			return v
+		}
+	}
+	return atcoder.VerdictSuccess
+}
+
+// NetworkError is returned by App.Run when communication with AtCoder, including login, fails.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
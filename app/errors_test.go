@@ -0,0 +1,51 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func TestFailureError(t *testing.T) {
+	tests := []struct {
+		name            string
+		inputError      *FailureError
+		expectedMessage string
+		expectedVerdict atcoder.Verdict
+	}{
+		{
+			name:            "wrong answer",
+			inputError:      &FailureError{Target: "samples", Summary: atcoder.Summary{atcoder.VerdictSuccess: 2, atcoder.VerdictFailure: 1}},
+			expectedMessage: "1 of 3 samples failed (FAILURE: 1)",
+			expectedVerdict: atcoder.VerdictFailure,
+		},
+		{
+			name:            "most severe verdict",
+			inputError:      &FailureError{Target: "samples", Summary: atcoder.Summary{atcoder.VerdictFailure: 1, atcoder.VerdictTLE: 1, atcoder.VerdictRE: 1}},
+			expectedMessage: "3 of 3 samples failed (FAILURE: 1, TLE: 1, RE: 1)",
+			expectedVerdict: atcoder.VerdictRE,
+		},
+		{
+			name:            "submission",
+			inputError:      &FailureError{Target: "submission", Summary: atcoder.Summary{atcoder.VerdictTLE: 1}},
+			expectedMessage: "1 of 1 submission failed (TLE: 1)",
+			expectedVerdict: atcoder.VerdictTLE,
+		},
+		{
+			name:            "compile error",
+			inputError:      &FailureError{Target: "build", Summary: atcoder.Summary{atcoder.VerdictCE: 1}},
+			expectedMessage: "failed to build your program",
+			expectedVerdict: atcoder.VerdictCE,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.inputError.Error() != test.expectedMessage {
+				t.Fatalf("message wrong. want=%s, got=%s", test.expectedMessage, test.inputError.Error())
+			}
+			if test.inputError.Verdict() != test.expectedVerdict {
+				t.Fatalf("verdict wrong. want=%s, got=%s", test.expectedVerdict, test.inputError.Verdict())
+			}
+		})
+	}
+}
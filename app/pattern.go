@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// defaultPathPatterns are the layouts of source files tried in order if the pattern is not specified.
+// e.g.) abc087/a.cpp, ABC087/A/main.rs
+var defaultPathPatterns = []string{"{contest}/{problem}.*", "{contest}/{problem}/*"}
+
+// contest ids contain numbers like "abc087" and problem ids are short like "A" or "Ex",
+// which keeps ordinary directories from being taken as contests or problems
+var patternPlaceholders = map[string]string{
+	"{contest}": `(?P<contest>[A-Za-z][A-Za-z_-]*[0-9][\w-]*)`,
+	"{problem}": `(?P<problem>[A-Za-z][0-9]?|[Ee][Xx])`,
+}
+
+// pathPattern matches the trailing components of paths, which can contain {contest}, {problem} and * (any part of a component).
+type pathPattern struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+func newPathPattern(pattern string) (*pathPattern, error) {
+	pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+	if !strings.Contains(pattern, "{contest}") || !strings.Contains(pattern, "{problem}") {
+		return nil, fmt.Errorf("path pattern '%s' should contain {contest} and {problem}", pattern)
+	}
+
+	var b strings.Builder
+	b.WriteString(`(?:^|/)`)
+	for rest := pattern; rest != ""; {
+		if strings.HasPrefix(rest, "*") {
+			b.WriteString(`[^/]*`)
+			rest = rest[1:]
+			continue
+		}
+		matched := false
+		for placeholder, expr := range patternPlaceholders {
+			if strings.HasPrefix(rest, placeholder) {
+				b.WriteString(expr)
+				rest = rest[len(placeholder):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteString(regexp.QuoteMeta(rest[:1]))
+			rest = rest[1:]
+		}
+	}
+	b.WriteString(`$`)
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern '%s': %s", pattern, err)
+	}
+	return &pathPattern{pattern: pattern, regexp: re}, nil
+}
+
+// match returns the contest and the problem in the path. ok is false if the path does not match.
+func (p *pathPattern) match(path string) (contest, problem string, ok bool) {
+	m := p.regexp.FindStringSubmatch(filepath.ToSlash(path))
+	if m == nil {
+		return "", "", false
+	}
+	for i, name := range p.regexp.SubexpNames() {
+		switch name {
+		case "contest":
+			contest = m[i]
+		case "problem":
+			problem = m[i]
+		}
+	}
+	return contest, problem, true
+}
+
+// dir returns the pattern of the directory of the files, which is used to match the working directory.
+// nil is returned if the directory does not identify the problem.
+func (p *pathPattern) dir() *pathPattern {
+	i := strings.LastIndex(p.pattern, "/")
+	if i < 0 {
+		return nil
+	}
+	d, err := newPathPattern(p.pattern[:i])
+	if err != nil {
+		return nil
+	}
+	return d
+}
+
+// inferProblem infers the contest and the problem from the source file, or the working directory if the file is empty.
+// the patterns are tried in order, and ok is false if none of them matches.
+func inferProblem(patterns []string, file, workDir string) (contest, problem string, ok bool, err error) {
+	for _, pattern := range patterns {
+		p, err := newPathPattern(pattern)
+		if err != nil {
+			return "", "", false, err
+		}
+
+		target := workDir
+		if file != "" {
+			target = file
+			if !filepath.IsAbs(file) && workDir != "" {
+				target = filepath.Join(workDir, file)
+			}
+		} else if p = p.dir(); p == nil {
+			continue
+		}
+
+		if contest, problem, ok := p.match(target); ok {
+			return contest, problem, true, nil
+		}
+	}
+	return "", "", false, nil
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_inferProblem(t *testing.T) {
+	tests := []struct {
+		name            string
+		inputPatterns   []string
+		inputFile       string
+		inputWorkDir    string
+		expectedContest string
+		expectedProblem string
+		expectedOK      bool
+		expectedErrMsg  string
+	}{
+		{
+			name:            "success-file in contest directory",
+			inputPatterns:   defaultPathPatterns,
+			inputFile:       "a.cpp",
+			inputWorkDir:    "/home/mui87/abc087",
+			expectedContest: "abc087",
+			expectedProblem: "a",
+			expectedOK:      true,
+		},
+		{
+			name:            "success-relative path of file",
+			inputPatterns:   defaultPathPatterns,
+			inputFile:       "abc087/a.cpp",
+			inputWorkDir:    "/home/mui87",
+			expectedContest: "abc087",
+			expectedProblem: "a",
+			expectedOK:      true,
+		},
+		{
+			name:            "success-file in problem directory",
+			inputPatterns:   defaultPathPatterns,
+			inputFile:       "/home/mui87/ABC087/A/main.rs",
+			inputWorkDir:    "/home/mui87",
+			expectedContest: "ABC087",
+			expectedProblem: "A",
+			expectedOK:      true,
+		},
+		{
+			name:            "success-problem Ex",
+			inputPatterns:   defaultPathPatterns,
+			inputFile:       "abc300/ex.py",
+			inputWorkDir:    "/home/mui87",
+			expectedContest: "abc300",
+			expectedProblem: "ex",
+			expectedOK:      true,
+		},
+		{
+			name:            "success-working directory",
+			inputPatterns:   defaultPathPatterns,
+			inputWorkDir:    "/home/mui87/ABC087/A",
+			expectedContest: "ABC087",
+			expectedProblem: "A",
+			expectedOK:      true,
+		},
+		{
+			name:            "success-custom pattern",
+			inputPatterns:   []string{"{contest}/tasks/{problem}_*.py"},
+			inputFile:       "arc100/tasks/c_solve.py",
+			inputWorkDir:    "/home/mui87",
+			expectedContest: "arc100",
+			expectedProblem: "c",
+			expectedOK:      true,
+		},
+		{
+			name:          "success-working directory not identifying problem",
+			inputPatterns: defaultPathPatterns,
+			inputWorkDir:  "/home/mui87/abc087",
+			expectedOK:    false,
+		},
+		{
+			name:          "success-ordinary directories",
+			inputPatterns: defaultPathPatterns,
+			inputFile:     "main.go",
+			inputWorkDir:  "/home/mui87/work",
+			expectedOK:    false,
+		},
+		{
+			name:           "failure-pattern without problem",
+			inputPatterns:  []string{"{contest}/*"},
+			inputFile:      "a.cpp",
+			inputWorkDir:   "/home/mui87/abc087",
+			expectedErrMsg: "should contain {contest} and {problem}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contest, problem, ok, err := inferProblem(test.inputPatterns, test.inputFile, test.inputWorkDir)
+
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if ok != test.expectedOK {
+					t.Fatalf("ok should be %t. got: %t", test.expectedOK, ok)
+				}
+				if contest != test.expectedContest || problem != test.expectedProblem {
+					t.Errorf("contest/problem should be %s/%s. got: %s/%s", test.expectedContest, test.expectedProblem, contest, problem)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("err should not be nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Errorf("err message should contain '%s'. got: %s", test.expectedErrMsg, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Preset defines how to build and run a source file. the commands can contain placeholders:
+// {file} (path of the source file), {dir} (directory of the source file),
+// {name} (file name without extension) and {tmp} (temporary directory for build artifacts).
+// Submit is a part of the language name on the submit page of AtCoder, which is used on submission.
+// Template is the path of the file copied as the source file of each problem by new command.
+type Preset struct {
+	Build    string `toml:"build" yaml:"build"`
+	Run      string `toml:"run" yaml:"run"`
+	Submit   string `toml:"submit" yaml:"submit"`
+	Template string `toml:"template" yaml:"template"`
+}
+
+var defaultPresets = map[string]Preset{
+	"cpp": {
+		Build:  "g++ -std=gnu++17 -O2 -DONLINE_JUDGE -DATCODER -o {tmp}/a.out {file}",
+		Run:    "{tmp}/a.out",
+		Submit: "C++",
+	},
+	"c": {
+		Build:  "gcc -std=gnu11 -O2 -DONLINE_JUDGE -DATCODER -o {tmp}/a.out {file} -lm",
+		Run:    "{tmp}/a.out",
+		Submit: "C (",
+	},
+	"python": {
+		Run:    "python3 {file}",
+		Submit: "Python (",
+	},
+	"pypy": {
+		Run:    "pypy3 {file}",
+		Submit: "PyPy",
+	},
+	"rust": {
+		Build:  "rustc --edition 2021 -O -o {tmp}/a.out {file}",
+		Run:    "{tmp}/a.out",
+		Submit: "Rust",
+	},
+	"go": {
+		Build:  "go build -o {tmp}/a.out {file}",
+		Run:    "{tmp}/a.out",
+		Submit: "Go (",
+	},
+	"java": {
+		Build:  "javac -d {tmp} {file}",
+		Run:    "java -cp {tmp} Main",
+		Submit: "Java (",
+	},
+	"ruby": {
+		Run:    "ruby {file}",
+		Submit: "Ruby",
+	},
+	"javascript": {
+		Run:    "node {file}",
+		Submit: "JavaScript",
+	},
+}
+
+var defaultExtensions = map[string]string{
+	".cpp":  "cpp",
+	".cc":   "cpp",
+	".cxx":  "cpp",
+	".c":    "c",
+	".py":   "python",
+	".rs":   "rust",
+	".go":   "go",
+	".java": "java",
+	".rb":   "ruby",
+	".js":   "javascript",
+}
+
+// selectPreset returns the preset for the language, or for the extension of the file if language is empty.
+// presets and extensions in config take precedence over the default ones. fields not set in the config are taken from the default preset.
+func selectPreset(file, language string, cfg *config) (Preset, error) {
+	if language == "" {
+		ext := strings.ToLower(filepath.Ext(file))
+		var ok bool
+		if language, ok = cfg.Extensions[ext]; !ok {
+			if language, ok = defaultExtensions[ext]; !ok {
+				// the language in the config is only the fallback, so that it does not override the extensions
+				if language = cfg.Language; language == "" {
+					return Preset{}, fmt.Errorf("could not detect the language of '%s'. specify the language. e.g.) -lang cpp", file)
+				}
+			}
+		}
+	}
+
+	preset, ok := defaultPresets[language]
+	if custom, found := cfg.Presets[language]; found {
+		preset, ok = preset.override(custom), true
+	}
+	if ok {
+		return preset, nil
+	}
+	return Preset{}, fmt.Errorf("unknown language '%s'. available languages: %s", language, strings.Join(languages(cfg), ", "))
+}
+
+// override returns the preset whose fields are replaced with the ones set in other.
+func (p Preset) override(other Preset) Preset {
+	if other.Build != "" {
+		p.Build = other.Build
+	}
+	if other.Run != "" {
+		p.Run = other.Run
+	}
+	if other.Submit != "" {
+		p.Submit = other.Submit
+	}
+	if other.Template != "" {
+		p.Template = other.Template
+	}
+	return p
+}
+
+func (p Preset) expand(file, tmpDirPath string) Preset {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	replacer := strings.NewReplacer(
+		"{file}", file,
+		"{dir}", filepath.Dir(file),
+		"{name}", name,
+		"{tmp}", tmpDirPath,
+	)
+	return Preset{
+		Build:    replacer.Replace(p.Build),
+		Run:      replacer.Replace(p.Run),
+		Submit:   p.Submit,
+		Template: p.Template,
+	}
+}
+
+func languages(cfg *config) []string {
+	var names []string
+	for name := range defaultPresets {
+		names = append(names, name)
+	}
+	for name := range cfg.Presets {
+		if _, ok := defaultPresets[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectPreset(t *testing.T) {
+	cfg, err := loadConfig("testdata/config.toml")
+	if err != nil {
+		t.Fatalf("failed to load config: %s", err)
+	}
+
+	tests := []struct {
+		name           string
+		inputFile      string
+		inputLanguage  string
+		inputConfig    *config
+		expectedPreset Preset
+		expectedErrMsg string
+	}{
+		{
+			name:           "success-default preset by extension",
+			inputFile:      "abc/087/a.rb",
+			inputConfig:    &config{},
+			expectedPreset: defaultPresets["ruby"],
+		},
+		{
+			name:           "success-default preset by language",
+			inputFile:      "a.py",
+			inputLanguage:  "pypy",
+			inputConfig:    &config{},
+			expectedPreset: defaultPresets["pypy"],
+		},
+		{
+			name:           "success-preset overridden by config",
+			inputFile:      "a.cpp",
+			inputConfig:    cfg,
+			expectedPreset: Preset{Build: "clang++ -std=c++17 -O2 -o {tmp}/a.out {file}", Run: "{tmp}/a.out", Submit: "C++"},
+		},
+		{
+			name:           "success-only template set by config",
+			inputFile:      "a.rb",
+			inputConfig:    cfg,
+			expectedPreset: Preset{Run: "ruby {file}", Submit: "Ruby", Template: "~/.atctest/template.rb"},
+		},
+		{
+			name:           "success-extension overridden by config",
+			inputFile:      "a.py",
+			inputConfig:    cfg,
+			expectedPreset: defaultPresets["pypy"],
+		},
+		{
+			name:           "success-preset added by config",
+			inputFile:      "a.nim",
+			inputConfig:    cfg,
+			expectedPreset: Preset{Build: "nim cpp -d:release -o:{tmp}/a.out {file}", Run: "{tmp}/a.out"},
+		},
+		{
+			name:           "success-extension preferred to language of config",
+			inputFile:      "abc124/b.cpp",
+			inputConfig:    &config{Language: "pypy"},
+			expectedPreset: defaultPresets["cpp"],
+		},
+		{
+			name:           "success-language of config for unknown extension",
+			inputFile:      "a.txt",
+			inputConfig:    &config{Language: "pypy"},
+			expectedPreset: defaultPresets["pypy"],
+		},
+		{
+			name:           "success-language preferred to extension",
+			inputFile:      "a.cpp",
+			inputLanguage:  "pypy",
+			inputConfig:    &config{Language: "ruby"},
+			expectedPreset: defaultPresets["pypy"],
+		},
+		{
+			name:           "failure-unknown extension",
+			inputFile:      "a.cob",
+			inputConfig:    &config{},
+			expectedErrMsg: "could not detect the language of 'a.cob'",
+		},
+		{
+			name:           "failure-unknown language",
+			inputFile:      "a.py",
+			inputLanguage:  "python2",
+			inputConfig:    &config{},
+			expectedErrMsg: "unknown language 'python2'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			preset, err := selectPreset(test.inputFile, test.inputLanguage, test.inputConfig)
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if preset != test.expectedPreset {
+					t.Fatalf("preset wrong. want=%+v, got=%+v", test.expectedPreset, preset)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestPreset_expand(t *testing.T) {
+	preset := Preset{
+		Build: "cargo build --release --bin {name} && g++ -o {tmp}/a.out {file}",
+		Run:   "cd {dir} && {tmp}/a.out",
+	}
+	expected := Preset{
+		Build: "cargo build --release --bin a && g++ -o /tmp/atctest/a.out abc087/a.cpp",
+		Run:   "cd abc087 && /tmp/atctest/a.out",
+	}
+
+	actual := preset.expand("abc087/a.cpp", "/tmp/atctest")
+	if actual != expected {
+		t.Fatalf("expanded preset wrong. want=%+v, got=%+v", expected, actual)
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// the template is copied as this name with its extension. e.g.) main.cpp
+const sourceFileName = "main"
+
+// scaffold creates the directory of each problem of the contest as {contest}/{problem}/,
+// which contains samples in the layout and the source file copied from the template.
+func (a *App) scaffold() error {
+	if err := a.logInIfBeingHeld(); err != nil {
+		return err
+	}
+
+	problems, err := a.client.GetProblemList(a.contest)
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+
+	var template []byte
+	if a.template != "" {
+		if template, err = ioutil.ReadFile(a.template); err != nil {
+			return fmt.Errorf("failed to read template: %s", err)
+		}
+	}
+
+	contestDir := filepath.Join(a.dir, strings.ToLower(a.contest))
+	for _, p := range problems {
+		problemDir := filepath.Join(contestDir, strings.ToLower(p.ID))
+		if err := os.MkdirAll(problemDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory for problem %s: %s", p.ID, err)
+		}
+
+		if template != nil {
+			sourceFile := filepath.Join(problemDir, sourceFileName+filepath.Ext(a.template))
+			if err := copyTemplate(sourceFile, template); err != nil {
+				return err
+			}
+		}
+
+		// samples of other problems are still useful even if one of them fails
+		problem, err := a.client.GetProblem(p.URL)
+		if err != nil {
+			_, _ = fmt.Fprintf(a.errStream, "[WARNING] could not get samples of problem %s: %s\n", p.ID, err)
+			continue
+		}
+		if _, err := atcoder.WriteSamples(filepath.Join(problemDir, atcoder.DefaultSampleDir(a.sampleLayout)), a.sampleLayout, problem.Samples); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(a.outStream, "%s - %s: %d samples in %s\n", p.ID, p.Title, len(problem.Samples), problemDir)
+	}
+	return nil
+}
+
+// download writes the samples of the problem into the directory in the layout.
+func (a *App) download() error {
+	if err := a.logInIfBeingHeld(); err != nil {
+		return err
+	}
+
+	problemURL, err := a.getProblemURL()
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	problem, err := a.client.GetProblem(problemURL)
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+
+	paths, err := atcoder.WriteSamples(a.dir, a.sampleLayout, problem.Samples)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		_, _ = fmt.Fprintln(a.outStream, p)
+	}
+	return nil
+}
+
+// copyTemplate writes the template as the source file. existing files are kept not to lose your code.
+func copyTemplate(sourceFile string, template []byte) error {
+	if _, err := os.Stat(sourceFile); err == nil {
+		return nil
+	}
+	if err := ioutil.WriteFile(sourceFile, template, 0644); err != nil {
+		return fmt.Errorf("failed to copy template: %s", err)
+	}
+	return nil
+}
@@ -3,59 +3,269 @@ package atcoder
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/mui87/atctest/commander"
 )
 
+type Verdict string
+
+const (
+	VerdictSuccess Verdict = "SUCCESS"
+	VerdictFailure Verdict = "FAILURE"
+	VerdictTLE     Verdict = "TLE"
+	VerdictMLE     Verdict = "MLE"
+	VerdictRE      Verdict = "RE"
+	VerdictCE      Verdict = "CE"
+	VerdictError   Verdict = "ERROR"
+)
+
+var Verdicts = []Verdict{VerdictSuccess, VerdictFailure, VerdictTLE, VerdictMLE, VerdictRE, VerdictCE, VerdictError}
+
+// Summary is the number of samples (or trials) for each verdict.
+type Summary map[Verdict]int
+
+func (s Summary) Total() int {
+	total := 0
+	for _, n := range s {
+		total += n
+	}
+	return total
+}
+
+func (s Summary) Success() bool {
+	return s[VerdictSuccess] == s.Total()
+}
+
+// String returns counts of verdicts except SUCCESS. e.g.) "FAILURE: 1, TLE: 2"
+func (s Summary) String() string {
+	var counts []string
+	for _, v := range Verdicts {
+		if v != VerdictSuccess && s[v] > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", v, s[v]))
+		}
+	}
+	return strings.Join(counts, ", ")
+}
+
+type SampleResult struct {
+	Verdict  Verdict
+	Output   string
+	Stderr   string
+	ExitCode int
+	Signal   string
+	Message  string
+	Time     time.Duration
+	CPUTime  time.Duration
+	Memory   int64
+}
+
+const stderrTailLines = 20
+
 type Checker struct {
-	commander commander.Commander
+	commander   commander.Commander
+	interactive commander.Interactive
+
+	// if true, address space of the program is limited to the memory limit by rlimit
+	enforceMemoryLimit bool
+	// max number of samples run concurrently
+	parallel int
+	// reports results of samples
+	formatter Formatter
+	// colors of messages such as build results
+	palette palette
+
 	outStream io.Writer
 	errStream io.Writer
 }
 
-func NewChecker(outStream, errStream io.Writer) *Checker {
+func NewChecker(enforceMemoryLimit bool, parallel int, colorMode string, formatter Formatter, outStream, errStream io.Writer) *Checker {
+	external := commander.NewExternal()
 	return &Checker{
-		commander: commander.NewExternal(),
-		outStream: outStream,
-		errStream: errStream,
+		commander:          external,
+		interactive:        external,
+		enforceMemoryLimit: enforceMemoryLimit,
+		parallel:           parallel,
+		formatter:          formatter,
+		palette:            palette(colorMode),
+		outStream:          outStream,
+		errStream:          errStream,
 	}
 }
 
-func (c *Checker) Check(command string, samples []Sample) bool {
-	successAll := true
+// Build executes the build command such as compilation once before running samples.
+func (c *Checker) Build(buildCommand string) bool {
+	result, err := c.commander.Run(buildCommand, "", commander.Limit{})
+	_, _ = fmt.Fprint(c.outStream, "build: ")
+	if err != nil {
+		_, _ = c.palette.color(color.FgRed).Fprintln(c.outStream, VerdictError)
+		_, _ = fmt.Fprintln(c.outStream, err.Error())
+		return false
+	}
+	if !result.Succeeded() {
+		_, _ = c.palette.color(color.FgYellow).Fprint(c.outStream, VerdictCE)
+		_, _ = fmt.Fprintf(c.outStream, " (exit code %d)\n", result.ExitCode)
+		_, _ = fmt.Fprintln(c.outStream, "compiler output:")
+		_, _ = fmt.Fprint(c.outStream, result.Stdout)
+		_, _ = fmt.Fprint(c.outStream, result.Stderr)
+		return false
+	}
+
+	_, _ = c.palette.color(color.FgGreen).Fprint(c.outStream, VerdictSuccess)
+	_, _ = fmt.Fprintf(c.outStream, " [time: %d ms]\n", result.Time.Milliseconds())
+	return true
+}
+
+func (c *Checker) Check(command string, samples []Sample, judge Judge, timeLimit time.Duration, memoryLimit int64) Summary {
+	results := c.checkAll(command, samples, judge, timeLimit, memoryLimit)
+
+	c.formatter.Begin(CheckSettings{
+		Judge:               judge.Name(),
+		Samples:             len(samples),
+		TimeLimit:           timeLimit,
+		MemoryLimit:         memoryLimit,
+		MemoryLimitEnforced: c.enforceMemoryLimit,
+	})
+	summary := Summary{}
 	for i, sample := range samples {
-		success, actual, err := c.checkOne(command, sample)
-		_, _ = fmt.Fprintf(c.outStream, "sample %d: ", i+1)
-		if err != nil {
-			successAll = false
+		result := <-results[i]
+		summary[result.Verdict]++
+		c.formatter.Sample(i+1, sample, result)
+	}
+	c.formatter.End()
 
-			_, _ = color.New(color.FgRed).Fprintln(c.outStream, "ERROR")
-			_, _ = fmt.Fprintln(c.outStream, err.Error())
-		} else if success {
-			_, _ = color.New(color.FgGreen).Fprintln(c.outStream, "SUCCESS")
-		} else {
-			successAll = false
-
-			_, _ = color.New(color.FgRed).Fprintln(c.outStream, "FAILURE")
-			_, _ = fmt.Fprintln(c.outStream, "input:")
-			_, _ = fmt.Fprint(c.outStream, sample.Input)
-			_, _ = fmt.Fprintln(c.outStream, "expected output:")
-			_, _ = fmt.Fprint(c.outStream, sample.Output)
-			_, _ = fmt.Fprintln(c.outStream, "actual output:")
-			_, _ = fmt.Fprint(c.outStream, actual)
-		}
+	return summary
+}
+
+// checkAll runs samples with at most c.parallel workers.
+// i-th channel receives the result of i-th sample so that results can be reported in order of samples.
+func (c *Checker) checkAll(command string, samples []Sample, judge Judge, timeLimit time.Duration, memoryLimit int64) []chan *SampleResult {
+	parallel := c.parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]chan *SampleResult, len(samples))
+	for i := range results {
+		results[i] = make(chan *SampleResult, 1)
 	}
 
-	return successAll
+	go func() {
+		semaphore := make(chan struct{}, parallel)
+		for i, sample := range samples {
+			semaphore <- struct{}{}
+			go func(i int, sample Sample) {
+				defer func() { <-semaphore }()
+				results[i] <- c.checkOne(command, sample, judge, timeLimit, memoryLimit)
+			}(i, sample)
+		}
+	}()
+
+	return results
 }
 
-func (c *Checker) checkOne(command string, sample Sample) (bool, string, error) {
-	actualOutput, err := c.commander.Run(command, sample.Input)
+func (c *Checker) checkOne(command string, sample Sample, judge Judge, timeLimit time.Duration, memoryLimit int64) *SampleResult {
+	limit := commander.Limit{Time: timeLimit}
+	if c.enforceMemoryLimit {
+		limit.Memory = memoryLimit
+	}
+
+	runResult, err := c.commander.Run(command, sample.Input, limit)
+	if err != nil {
+		return &SampleResult{Verdict: VerdictError, Message: err.Error()}
+	}
+
+	result := &SampleResult{
+		Output:   runResult.Stdout,
+		Stderr:   runResult.Stderr,
+		ExitCode: runResult.ExitCode,
+		Signal:   runResult.Signal,
+		Time:     runResult.Time,
+		CPUTime:  runResult.CPUTime,
+		Memory:   runResult.Memory,
+	}
+	if runResult.TimedOut {
+		result.Verdict = VerdictTLE
+		return result
+	}
+	if memoryLimit > 0 && runResult.Memory > memoryLimit {
+		result.Verdict = VerdictMLE
+		return result
+	}
+	if !runResult.Succeeded() {
+		result.Verdict = VerdictRE
+		return result
+	}
+
+	success, message, err := judge.Judge(sample, runResult.Stdout)
 	if err != nil {
-		return false, "", err
+		return &SampleResult{Verdict: VerdictError, Message: err.Error()}
+	}
+	if success {
+		result.Verdict = VerdictSuccess
+	} else {
+		result.Verdict = VerdictFailure
+		result.Message = message
+	}
+
+	return result
+}
+
+// tailLines returns the last n lines of s, which ends with a newline.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = append([]string{fmt.Sprintf("... (%d lines omitted)", len(lines)-n)}, lines[len(lines)-n:]...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// CheckInteractive runs the program with the interactor for the given number of trials.
+// the interactor is executed with the trial number as its argument so that it can change the hidden case.
+func (c *Checker) CheckInteractive(command, interactorCommand string, trials, queryLimit int, timeLimit time.Duration) Summary {
+	summary := Summary{}
+	for i := 1; i <= trials; i++ {
+		interaction, err := c.interactive.RunInteractive(command, fmt.Sprintf("%s %d", interactorCommand, i), queryLimit, timeLimit)
+		_, _ = fmt.Fprintf(c.outStream, "trial %d: ", i)
+		if err != nil {
+			summary[VerdictError]++
+
+			_, _ = c.palette.color(color.FgRed).Fprintln(c.outStream, VerdictError)
+			_, _ = fmt.Fprintln(c.outStream, err.Error())
+			if interaction != nil {
+				_, _ = fmt.Fprintln(c.outStream, "transcript:")
+				_, _ = fmt.Fprint(c.outStream, interaction.Transcript)
+			}
+		} else if interaction.Accepted {
+			summary[VerdictSuccess]++
+
+			_, _ = c.palette.color(color.FgGreen).Fprint(c.outStream, VerdictSuccess)
+			_, _ = fmt.Fprintf(c.outStream, " (interactive, %d queries) [time: %d ms]\n", interaction.Queries, interaction.Time.Milliseconds())
+		} else {
+			if interaction.TimedOut {
+				summary[VerdictTLE]++
+				_, _ = c.palette.color(color.FgYellow).Fprint(c.outStream, VerdictTLE)
+			} else {
+				summary[VerdictFailure]++
+				_, _ = c.palette.color(color.FgRed).Fprint(c.outStream, VerdictFailure)
+			}
+			_, _ = fmt.Fprintf(c.outStream, " (interactive, %d queries) [time: %d ms]\n", interaction.Queries, interaction.Time.Milliseconds())
+			if interaction.TimedOut {
+				_, _ = fmt.Fprintf(c.outStream, "time limit: %d ms\n", timeLimit.Milliseconds())
+			}
+			if interaction.QueryLimitExceeded {
+				_, _ = fmt.Fprintf(c.outStream, "query limit exceeded. limit: %d\n", queryLimit)
+			}
+			_, _ = fmt.Fprintln(c.outStream, "transcript:")
+			_, _ = fmt.Fprint(c.outStream, interaction.Transcript)
+			if interaction.JudgeMessage != "" {
+				_, _ = fmt.Fprintln(c.outStream, "judge message:")
+				_, _ = fmt.Fprint(c.outStream, interaction.JudgeMessage)
+			}
+		}
 	}
-	success := actualOutput == sample.Output
 
-	return success, actualOutput, nil
+	return summary
 }
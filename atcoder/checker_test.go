@@ -3,16 +3,25 @@ package atcoder
 import (
 	"bytes"
 	"errors"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/mui87/atctest/commander"
 )
 
-const dummyRawCommand = "hello"
+const (
+	dummyRawCommand  = "hello"
+	dummyTimeLimit   = 2 * time.Second
+	dummyMemoryLimit = 1024 * 1024 * 1024
+)
 
 func TestChecker_Check(t *testing.T) {
 	tests := []struct {
 		name            string
 		inputSamples    []Sample
+		inputJudge      Judge
 		mockResults     []commandResult
 		expectedSuccess bool
 		expectedOutput  string
@@ -28,7 +37,31 @@ func TestChecker_Check(t *testing.T) {
 				{output: "3\n", err: nil},
 			},
 			expectedSuccess: true,
-			expectedOutput:  "SUCCESS",
+			expectedOutput:  "SUCCESS (exact)",
+		},
+		{
+			name: "success-within tolerance",
+			inputSamples: []Sample{
+				{Input: "1 3\n", Output: "0.333333333\n"},
+			},
+			inputJudge: &FloatJudge{tolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6}},
+			mockResults: []commandResult{
+				{output: "0.3333334\n", err: nil},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "SUCCESS (float)",
+		},
+		{
+			name: "failure-out of tolerance",
+			inputSamples: []Sample{
+				{Input: "1 3\n", Output: "0.333333333\n"},
+			},
+			inputJudge: &FloatJudge{tolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6}},
+			mockResults: []commandResult{
+				{output: "0.3334\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "FAILURE (float) [time: 0 ms, cpu: 0 ms, memory: 0 KB]\ninput:\n1 3",
 		},
 		{
 			name: "failure-all failed",
@@ -41,7 +74,7 @@ func TestChecker_Check(t *testing.T) {
 				{output: "99\n", err: nil},
 			},
 			expectedSuccess: false,
-			expectedOutput:  "FAILURE\ninput:\n0 1",
+			expectedOutput:  "FAILURE (exact) [time: 0 ms, cpu: 0 ms, memory: 0 KB]\ninput:\n0 1",
 		},
 		{
 			name: "failure-some failed",
@@ -54,7 +87,67 @@ func TestChecker_Check(t *testing.T) {
 				{output: "99\n", err: nil},
 			},
 			expectedSuccess: false,
-			expectedOutput:  "FAILURE\ninput:\n1 2",
+			expectedOutput:  "FAILURE (exact) [time: 0 ms, cpu: 0 ms, memory: 0 KB]\ninput:\n1 2",
+		},
+		{
+			name: "failure-special judge message",
+			inputSamples: []Sample{
+				{Input: "3\n", Output: "1 2 3\n"},
+			},
+			inputJudge: &SpecialJudge{command: "judge", commander: &testCommander{index: 0, results: []commandResult{
+				{output: "", exitCode: 1, stderr: "not a permutation\n", err: nil},
+			}}},
+			mockResults: []commandResult{
+				{output: "1 1 1\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "judge message:\nnot a permutation",
+		},
+		{
+			name: "failure-time limit exceeded",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+				{Input: "1 2\n", Output: "3\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n", err: nil},
+				{output: "", timedOut: true, err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "TLE [time: 0 ms, cpu: 0 ms, memory: 0 KB]\ntime limit: 2000 ms\ninput:\n1 2",
+		},
+		{
+			name: "failure-memory limit exceeded",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n", memory: 2 * 1024 * 1024 * 1024, err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "MLE [time: 0 ms, cpu: 0 ms, memory: 2097152 KB]\nmemory limit: 1048576 KB\ninput:\n0 1",
+		},
+		{
+			name: "failure-runtime error with signal",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "", stderr: "Segmentation fault (core dumped)\n", exitCode: 139, signal: "SIGSEGV", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "RE (SIGSEGV) [time: 0 ms, cpu: 0 ms, memory: 0 KB]\nstderr:\nSegmentation fault (core dumped)\ninput:\n0 1",
+		},
+		{
+			name: "failure-runtime error with exit code",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "", stderr: "Traceback (most recent call last):\nZeroDivisionError: division by zero\n", exitCode: 1, err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "RE (exit code 1) [time: 0 ms, cpu: 0 ms, memory: 0 KB]\nstderr:\nTraceback (most recent call last):\nZeroDivisionError: division by zero\ninput:\n0 1",
 		},
 		{
 			name: "failure-some error",
@@ -75,10 +168,167 @@ func TestChecker_Check(t *testing.T) {
 			var outStream bytes.Buffer
 			c := &Checker{
 				commander: &testCommander{index: 0, results: test.mockResults},
+				formatter: NewHumanFormatter(&outStream, DiffOption{}),
 				outStream: &outStream,
 			}
 
-			actualSuccess := c.Check(dummyRawCommand, test.inputSamples)
+			judge := test.inputJudge
+			if judge == nil {
+				judge = &ExactJudge{}
+			}
+
+			actualSuccess := c.Check(dummyRawCommand, test.inputSamples, judge, dummyTimeLimit, dummyMemoryLimit).Success()
+			if actualSuccess != test.expectedSuccess {
+				t.Fatalf("success wrong. want=%t, got=%t", test.expectedSuccess, actualSuccess)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestChecker_Check_parallel(t *testing.T) {
+	samples := []Sample{
+		{Input: "30\n", Output: "30\n"},
+		{Input: "20\n", Output: "99\n"},
+		{Input: "10\n", Output: "10\n"},
+	}
+
+	var outStream bytes.Buffer
+	c := &Checker{
+		commander: &echoCommander{},
+		parallel:  3,
+		formatter: NewHumanFormatter(&outStream, DiffOption{}),
+		outStream: &outStream,
+	}
+
+	summary := c.Check(dummyRawCommand, samples, &ExactJudge{}, dummyTimeLimit, dummyMemoryLimit)
+	if summary[VerdictSuccess] != 2 || summary[VerdictFailure] != 1 {
+		t.Fatalf("summary wrong. want=2 SUCCESS and 1 FAILURE, got=%v", summary)
+	}
+
+	var verdicts []string
+	for _, line := range strings.Split(outStream.String(), "\n") {
+		if strings.HasPrefix(line, "sample ") {
+			verdicts = append(verdicts, strings.Join(strings.Fields(line)[:3], " "))
+		}
+	}
+	expected := []string{"sample 1: SUCCESS", "sample 2: FAILURE", "sample 3: SUCCESS"}
+	if strings.Join(verdicts, ", ") != strings.Join(expected, ", ") {
+		t.Fatalf("results should be reported in order of samples. want=%v, got=%v", expected, verdicts)
+	}
+}
+
+func TestChecker_Build(t *testing.T) {
+	tests := []struct {
+		name            string
+		mockResult      commandResult
+		expectedSuccess bool
+		expectedOutput  string
+	}{
+		{
+			name:            "success",
+			mockResult:      commandResult{output: "", err: nil},
+			expectedSuccess: true,
+			expectedOutput:  "build: SUCCESS",
+		},
+		{
+			name:            "failure-compile error",
+			mockResult:      commandResult{output: "", stderr: "a.cpp:1:1: error: 'x' does not name a type\n", exitCode: 1, err: nil},
+			expectedSuccess: false,
+			expectedOutput:  "build: CE (exit code 1)\ncompiler output:\na.cpp:1:1: error: 'x' does not name a type",
+		},
+		{
+			name:            "failure-error",
+			mockResult:      commandResult{output: "", err: errors.New("some error")},
+			expectedSuccess: false,
+			expectedOutput:  "build: ERROR\nsome error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream bytes.Buffer
+			c := &Checker{
+				commander: &testCommander{index: 0, results: []commandResult{test.mockResult}},
+				outStream: &outStream,
+			}
+
+			actualSuccess := c.Build(dummyRawCommand)
+			if actualSuccess != test.expectedSuccess {
+				t.Fatalf("success wrong. want=%t, got=%t", test.expectedSuccess, actualSuccess)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestChecker_CheckInteractive(t *testing.T) {
+	tests := []struct {
+		name            string
+		inputTrials     int
+		mockResults     []interactiveResult
+		expectedSuccess bool
+		expectedOutput  string
+	}{
+		{
+			name:        "success",
+			inputTrials: 2,
+			mockResults: []interactiveResult{
+				{interaction: &commander.Interaction{Accepted: true, Queries: 3}},
+				{interaction: &commander.Interaction{Accepted: true, Queries: 4}},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "trial 2: SUCCESS (interactive, 4 queries)",
+		},
+		{
+			name:        "failure-wrong answer",
+			inputTrials: 1,
+			mockResults: []interactiveResult{
+				{interaction: &commander.Interaction{Accepted: false, Queries: 1, Transcript: "> ? 1\n< >=\n> ! 1\n", JudgeMessage: "wrong answer\n"}},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "transcript:\n> ? 1\n< >=\n> ! 1\njudge message:\nwrong answer",
+		},
+		{
+			name:        "failure-query limit exceeded",
+			inputTrials: 1,
+			mockResults: []interactiveResult{
+				{interaction: &commander.Interaction{Accepted: false, QueryLimitExceeded: true, Queries: 11}},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "query limit exceeded",
+		},
+		{
+			name:        "failure-time limit exceeded",
+			inputTrials: 1,
+			mockResults: []interactiveResult{
+				{interaction: &commander.Interaction{Accepted: false, TimedOut: true, Queries: 2}},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "TLE (interactive, 2 queries) [time: 0 ms]\ntime limit: 2000 ms",
+		},
+		{
+			name:        "failure-error",
+			inputTrials: 1,
+			mockResults: []interactiveResult{
+				{interaction: &commander.Interaction{}, err: errors.New("some error")},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "ERROR\nsome error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream bytes.Buffer
+			c := &Checker{
+				interactive: &testInteractive{index: 0, results: test.mockResults},
+				outStream:   &outStream,
+			}
+
+			actualSuccess := c.CheckInteractive(dummyRawCommand, dummyRawCommand, test.inputTrials, 10, dummyTimeLimit).Success()
 			if actualSuccess != test.expectedSuccess {
 				t.Fatalf("success wrong. want=%t, got=%t", test.expectedSuccess, actualSuccess)
 			}
@@ -90,8 +340,13 @@ func TestChecker_Check(t *testing.T) {
 }
 
 type commandResult struct {
-	output string
-	err    error
+	output   string
+	stderr   string
+	exitCode int
+	signal   string
+	timedOut bool
+	memory   int64
+	err      error
 }
 
 type testCommander struct {
@@ -99,12 +354,99 @@ type testCommander struct {
 	results []commandResult
 }
 
-func (t *testCommander) Run(command, stdin string) (string, error) {
+func (t *testCommander) Run(command, stdin string, limit commander.Limit) (*commander.Result, error) {
 	if t.index >= len(t.results) {
 		panic("index of testCommander out of range")
 	}
 	result := t.results[t.index]
 	t.index++
 
-	return result.output, result.err
+	if result.err != nil {
+		return nil, result.err
+	}
+	return &commander.Result{
+		Stdout:   result.output,
+		Stderr:   result.stderr,
+		ExitCode: result.exitCode,
+		Signal:   result.signal,
+		TimedOut: result.timedOut,
+		Memory:   result.memory,
+	}, nil
+}
+
+func TestSummary_String(t *testing.T) {
+	summary := Summary{VerdictSuccess: 3, VerdictRE: 1, VerdictFailure: 2}
+	if summary.Success() {
+		t.Fatal("success wrong. want=false, got=true")
+	}
+	if summary.Total() != 6 {
+		t.Fatalf("total wrong. want=6, got=%d", summary.Total())
+	}
+	expected := "FAILURE: 2, RE: 1"
+	if summary.String() != expected {
+		t.Fatalf("string wrong. want=%s, got=%s", expected, summary.String())
+	}
+}
+
+func Test_tailLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputS   string
+		inputN   int
+		expected string
+	}{
+		{
+			name:     "shorter than n",
+			inputS:   "a\nb\n",
+			inputN:   3,
+			expected: "a\nb\n",
+		},
+		{
+			name:     "longer than n",
+			inputS:   "a\nb\nc\nd",
+			inputN:   2,
+			expected: "... (2 lines omitted)\nc\nd\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := tailLines(test.inputS, test.inputN)
+			if actual != test.expected {
+				t.Fatalf("tail wrong. want=%q, got=%q", test.expected, actual)
+			}
+		})
+	}
+}
+
+// echoCommander outputs stdin after sleeping milliseconds given as stdin.
+type echoCommander struct{}
+
+func (e *echoCommander) Run(command, stdin string, limit commander.Limit) (*commander.Result, error) {
+	ms, err := strconv.Atoi(strings.TrimSpace(stdin))
+	if err != nil {
+		return nil, err
+	}
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+
+	return &commander.Result{Stdout: stdin}, nil
+}
+
+type interactiveResult struct {
+	interaction *commander.Interaction
+	err         error
+}
+
+type testInteractive struct {
+	index   int
+	results []interactiveResult
+}
+
+func (t *testInteractive) RunInteractive(command, interactorCommand string, queryLimit int, timeout time.Duration) (*commander.Interaction, error) {
+	if t.index >= len(t.results) {
+		panic("index of testInteractive out of range")
+	}
+	result := t.results[t.index]
+	t.index++
+
+	return result.interaction, result.err
 }
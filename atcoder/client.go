@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/gocolly/colly"
 )
@@ -16,6 +17,33 @@ import (
 type Sample struct {
 	Input  string
 	Output string
+	// Name is the name of the local test case added by the user. it is empty for samples on the problem page.
+	Name string `json:",omitempty"`
+}
+
+// Label returns the name shown in results, which distinguishes local test cases from samples on the problem page.
+func (s Sample) Label(index int) string {
+	if s.Name != "" {
+		return "local " + s.Name
+	}
+	return fmt.Sprintf("sample %d", index)
+}
+
+type Problem struct {
+	Samples     []Sample
+	Tolerance   float64
+	TimeLimit   time.Duration
+	MemoryLimit int64 // in bytes
+}
+
+// problemCacheVersion is the version of the cache format of problems. caches of other versions are fetched again
+// so that fields added later, e.g. the time limit, are not read as zero from old caches.
+const problemCacheVersion = 2
+
+// problemCache is the problem cached with the version of the format.
+type problemCache struct {
+	Version int
+	*Problem
 }
 
 type Client struct {
@@ -25,12 +53,16 @@ type Client struct {
 	useCache     bool
 	cacheDirPath string
 
+	// true if the session stored by login command is used
+	sessionLoaded bool
+
 	outStream io.Writer
 	errStream io.Writer
 }
 
+// NewClient creates a client, which reuses the login session stored in cacheDirPath if exists.
 func NewClient(baseURL string, useCache bool, cacheDirPath string, outStream, errStream io.Writer) *Client {
-	return &Client{
+	c := &Client{
 		baseURL:      baseURL,
 		collector:    colly.NewCollector(),
 		useCache:     useCache,
@@ -38,6 +70,10 @@ func NewClient(baseURL string, useCache bool, cacheDirPath string, outStream, er
 		outStream:    outStream,
 		errStream:    errStream,
 	}
+	if err := c.loadSession(); err != nil {
+		_, _ = fmt.Fprintln(c.errStream, err.Error())
+	}
+	return c
 }
 
 func (c *Client) IsContestBeingHeld(contestURL string) (bool, error) {
@@ -53,9 +89,16 @@ func (c *Client) IsContestBeingHeld(contestURL string) (bool, error) {
 	return beingHeld, nil
 }
 
+// LogIn logs in to AtCoder. it does nothing if the client is already logged in by the stored session.
 func (c *Client) LogIn(username, password string) error {
+	if c.IsLoggedIn(username) {
+		return nil
+	}
 	if username == "" || password == "" {
-		return errors.New("you need to provide username and password as command line options to test for the contest being held")
+		if c.sessionLoaded {
+			return errors.New("the stored session has expired. run 'atctest login' again, or provide username and password")
+		}
+		return errors.New("you need to provide username and password to test for the contest being held or to submit. set ATCTEST_USERNAME/ATCTEST_PASSWORD, or run 'atctest login'")
 	}
 
 	var (
@@ -80,7 +123,7 @@ func (c *Client) LogIn(username, password string) error {
 			loginErr = fmt.Errorf("login error: %s", err)
 			return
 		}
-		if !c.isLoggedIn(username) {
+		if !c.IsLoggedIn(username) {
 			loginErr = fmt.Errorf("login error: username/password may be wrong")
 			return
 		}
@@ -113,14 +156,58 @@ func (c *Client) GetProblemURL(contest, problem string) (string, error) {
 	return problemURL, nil
 }
 
-func (c *Client) GetSamples(problemURL string) ([]Sample, error) {
+// ProblemLink is a problem on the task list of a contest.
+type ProblemLink struct {
+	ID    string // e.g.) "A"
+	Title string
+	URL   string
+}
+
+// GetProblemList returns the problems of the contest in order of the task list.
+func (c *Client) GetProblemList(contest string) ([]ProblemLink, error) {
+	var problems []ProblemLink
+	c.collector.OnHTML(`tbody > tr`, func(e *colly.HTMLElement) {
+		links := e.DOM.Find(`td > a[href]`)
+		if links.Length() < 2 {
+			return
+		}
+		href, _ := links.First().Attr("href")
+		problems = append(problems, ProblemLink{
+			ID:    strings.TrimSpace(links.First().Text()),
+			Title: strings.TrimSpace(links.Eq(1).Text()),
+			URL:   c.baseURL + href,
+		})
+	})
+
+	problemListURL := fmt.Sprintf("%s/contests/%s/tasks", c.baseURL, strings.ToLower(contest))
+	if err := c.collector.Visit(problemListURL); err != nil {
+		return nil, fmt.Errorf("could not get HTML: %s", problemListURL)
+	}
+
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("could not find problems of contest '%s'. it may not have started yet", contest)
+	}
+	return problems, nil
+}
+
+func (c *Client) GetProblem(problemURL string) (*Problem, error) {
 	cacheFilePath := c.cacheFilePath(problemURL)
 	if c.useCache {
-		if samples, ok := c.getCachedSamples(cacheFilePath); ok {
-			return samples, nil
+		if problem, ok := c.getCachedProblem(cacheFilePath); ok {
+			return problem, nil
 		}
 	}
 
+	var statement, limits string
+	c.collector.OnHTML(`#task-statement`, func(e *colly.HTMLElement) {
+		statement = e.Text
+	})
+	c.collector.OnHTML(`p`, func(e *colly.HTMLElement) {
+		if limits == "" && timeLimitRegexp.MatchString(e.Text) {
+			limits = e.Text
+		}
+	})
+
 	elements, err := c.fetchSampleElements(problemURL)
 	if err != nil {
 		return nil, err
@@ -131,16 +218,25 @@ func (c *Client) GetSamples(problemURL string) ([]Sample, error) {
 		return nil, err
 	}
 
-	if err := c.cacheSamples(cacheFilePath, samples); err != nil {
+	problem := &Problem{
+		Samples:     samples,
+		Tolerance:   parseTolerance(statement),
+		TimeLimit:   parseTimeLimit(limits),
+		MemoryLimit: parseMemoryLimit(limits),
+	}
+
+	if err := c.cacheProblem(cacheFilePath, problem); err != nil {
 		_, _ = io.WriteString(c.errStream, err.Error())
 	}
 
-	return samples, nil
+	return problem, nil
 }
 
-func (c *Client) isLoggedIn(username string) bool {
+// IsLoggedIn checks the session cookie, which contains the username and the expiration time.
+// any user is accepted if username is empty.
+func (c *Client) IsLoggedIn(username string) bool {
 	for _, c := range c.collector.Cookies(c.baseURL) {
-		if strings.Contains(c.Value, "UserScreenName%3A"+username) {
+		if strings.Contains(c.Value, "UserScreenName%3A"+username) && !sessionExpired(c.Value, time.Now()) {
 			return true
 		}
 	}
@@ -153,7 +249,7 @@ func (c *Client) cacheFilePath(problemURL string) string {
 	return path.Join(c.cacheDirPath, filename)
 }
 
-func (c *Client) getCachedSamples(cacheFilePath string) ([]Sample, bool) {
+func (c *Client) getCachedProblem(cacheFilePath string) (*Problem, bool) {
 	_, err := os.Stat(c.cacheDirPath)
 	if err != nil {
 		return nil, false
@@ -164,15 +260,15 @@ func (c *Client) getCachedSamples(cacheFilePath string) ([]Sample, bool) {
 		return nil, false
 	}
 
-	var samples []Sample
-	if err := json.Unmarshal(bytes, &samples); err != nil {
+	cache := problemCache{Problem: &Problem{}}
+	if err := json.Unmarshal(bytes, &cache); err != nil || cache.Version != problemCacheVersion {
 		return nil, false
 	}
 
-	return samples, true
+	return cache.Problem, true
 }
 
-func (c *Client) cacheSamples(cacheFilePath string, samples []Sample) error {
+func (c *Client) cacheProblem(cacheFilePath string, problem *Problem) error {
 	_, err := os.Stat(c.cacheDirPath)
 	if os.IsNotExist(err) {
 		if err := os.MkdirAll(c.cacheDirPath, 0777); err != nil {
@@ -182,7 +278,7 @@ func (c *Client) cacheSamples(cacheFilePath string, samples []Sample) error {
 		return err
 	}
 
-	bytes, err := json.Marshal(samples)
+	bytes, err := json.Marshal(problemCache{Version: problemCacheVersion, Problem: problem})
 	if err != nil {
 		return err
 	}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gocolly/colly"
 
@@ -206,20 +208,108 @@ func TestClient_GetProblemURL(t *testing.T) {
 	}
 }
 
-func TestClient_GetSamples(t *testing.T) {
+func TestClient_GetProblemList(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputContest string
+
+		mockRequestPath string
+		mockStatusCode  int
+		mockHTMLFile    string
+
+		expectedProblems []ProblemLink
+		expectedErrMsg   string
+	}{
+		{
+			name:            "success-abc001",
+			inputContest:    "ABC001",
+			mockRequestPath: "/contests/abc001/tasks",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "abc001.html",
+			expectedProblems: []ProblemLink{
+				{ID: "A", Title: "積雪深差", URL: dummyBaseURL + "/contests/abc001/tasks/abc001_1"},
+				{ID: "B", Title: "視程の通報", URL: dummyBaseURL + "/contests/abc001/tasks/abc001_2"},
+				{ID: "C", Title: "風力観測", URL: dummyBaseURL + "/contests/abc001/tasks/abc001_3"},
+				{ID: "D", Title: "感雨時刻の整理", URL: dummyBaseURL + "/contests/abc001/tasks/abc001_4"},
+			},
+		},
+		{
+			name:            "success-arc103",
+			inputContest:    "arc103",
+			mockRequestPath: "/contests/arc103/tasks",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "arc103.html",
+			expectedProblems: []ProblemLink{
+				{ID: "C", Title: "/\\/\\/\\/", URL: dummyBaseURL + "/contests/arc103/tasks/arc103_a"},
+				{ID: "D", Title: "Robot Arms", URL: dummyBaseURL + "/contests/arc103/tasks/arc103_b"},
+				{ID: "E", Title: "Tr/ee", URL: dummyBaseURL + "/contests/arc103/tasks/arc103_c"},
+				{ID: "F", Title: "Distance Sums", URL: dummyBaseURL + "/contests/arc103/tasks/arc103_d"},
+			},
+		},
+		{
+			name:            "failure-xxx999_not_exist",
+			inputContest:    "xxx999",
+			mockRequestPath: "/contests/xxx999/tasks",
+			mockStatusCode:  http.StatusNotFound,
+			mockHTMLFile:    "xxx999.html",
+			expectedErrMsg:  "could not get HTML",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			html, err := ioutil.ReadFile(path.Join("testdata", "problem_list", test.mockHTMLFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer gock.Off()
+			gock.New(dummyBaseURL).
+				Get(test.mockRequestPath).
+				Reply(test.mockStatusCode).
+				AddHeader("Content-Type", "text/html").
+				BodyString(string(html))
+
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+			problems, err := c.GetProblemList(test.inputContest)
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if !reflect.DeepEqual(problems, test.expectedProblems) {
+					t.Fatalf("problems wrong.\nwant=%+v\ngot= %+v", test.expectedProblems, problems)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetProblem(t *testing.T) {
 	tests := []struct {
 		name string
 
 		inputProblemURL   string
 		inputUseCache     bool
 		inputCacheDirPath string
+		// content of the cache file written by earlier versions. the problem is cached in the current format if empty
+		inputCache string
 
 		mockRequestPath string
 		mockStatusCode  int
 		mockHTMLFile    string
 
-		expectedSamples []Sample
-		expectedErrMsg  string
+		expectedSamples     []Sample
+		expectedTolerance   float64
+		expectedTimeLimit   time.Duration
+		expectedMemoryLimit int64
+		expectedErrMsg      string
 	}{
 		{
 			name: "success-disable_cache",
@@ -232,6 +322,8 @@ func TestClient_GetSamples(t *testing.T) {
 			mockRequestPath: "contests/abc124/tasks/abc124_b",
 			mockHTMLFile:    "abc124b.html",
 
+			expectedTimeLimit:   2 * time.Second,
+			expectedMemoryLimit: 1024 << 20,
 			expectedSamples: []Sample{
 				{
 					Input: strings.Join([]string{
@@ -270,6 +362,8 @@ func TestClient_GetSamples(t *testing.T) {
 			mockRequestPath: "contests/abc124/tasks/abc124_b",
 			mockHTMLFile:    "abc124b.html",
 
+			expectedTimeLimit:   2 * time.Second,
+			expectedMemoryLimit: 1024 << 20,
 			expectedSamples: []Sample{
 				{
 					Input: strings.Join([]string{
@@ -297,6 +391,26 @@ func TestClient_GetSamples(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success-cache_without_version",
+
+			inputProblemURL:   dummyBaseURL + "/contests/abc124/tasks/abc124_b",
+			inputUseCache:     true,
+			inputCacheDirPath: dummyCacheDirPath,
+			inputCache:        `{"Samples":[{"Input":"1\n","Output":"1\n"}],"Tolerance":0}`,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/abc124/tasks/abc124_b",
+			mockHTMLFile:    "abc124b.html",
+
+			expectedTimeLimit:   2 * time.Second,
+			expectedMemoryLimit: 1024 << 20,
+			expectedSamples: []Sample{
+				{Input: "4\n6 5 6 8\n", Output: "3\n"},
+				{Input: "5\n4 5 3 5 4\n", Output: "3\n"},
+				{Input: "5\n9 5 6 8 4\n", Output: "1\n"},
+			},
+		},
 		{
 			name: "success-old_DOM_structure",
 
@@ -331,6 +445,9 @@ func TestClient_GetSamples(t *testing.T) {
 					Output: "43257.5\n",
 				},
 			},
+			expectedTolerance:   1e-2,
+			expectedTimeLimit:   2 * time.Second,
+			expectedMemoryLimit: 64 << 20,
 		},
 		{
 			name: "success-only_one_sample",
@@ -343,6 +460,8 @@ func TestClient_GetSamples(t *testing.T) {
 			mockRequestPath: "contests/kupc2015/tasks/kupc2015_a",
 			mockHTMLFile:    "kupc2015a.html",
 
+			expectedTimeLimit:   2 * time.Second,
+			expectedMemoryLimit: 256 << 20,
 			expectedSamples: []Sample{
 				{
 					Input: strings.Join([]string{
@@ -399,9 +518,12 @@ func TestClient_GetSamples(t *testing.T) {
 				if err := os.MkdirAll(dummyCacheDirPath, 0777); err != nil {
 					t.Fatalf("failed to create dummy cache dir: %s", err.Error())
 				}
-				b, err := json.Marshal(test.expectedSamples)
+				b, err := json.Marshal(problemCache{Version: problemCacheVersion, Problem: &Problem{Samples: test.expectedSamples, Tolerance: test.expectedTolerance, TimeLimit: test.expectedTimeLimit, MemoryLimit: test.expectedMemoryLimit}})
 				if err != nil {
-					t.Fatalf("failed to marshal samples: %s", err.Error())
+					t.Fatalf("failed to marshal problem: %s", err.Error())
+				}
+				if test.inputCache != "" {
+					b = []byte(test.inputCache)
 				}
 				escapedURL := strings.Replace(test.inputProblemURL, "/", "_", -1)
 				filename := fmt.Sprintf("%s.json", escapedURL)
@@ -412,7 +534,7 @@ func TestClient_GetSamples(t *testing.T) {
 
 			var errBuff bytes.Buffer
 			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), useCache: test.inputUseCache, cacheDirPath: test.inputCacheDirPath, errStream: &errBuff}
-			samples, err := c.GetSamples(test.inputProblemURL)
+			problem, err := c.GetProblem(test.inputProblemURL)
 			if test.expectedErrMsg == "" {
 				if err != nil {
 					t.Fatalf("err should be nil. got: %s", err.Error())
@@ -420,6 +542,16 @@ func TestClient_GetSamples(t *testing.T) {
 				if errBuff.String() != "" {
 					t.Fatalf("errStream should be empty. got: %s", errBuff.String())
 				}
+				if problem.Tolerance != test.expectedTolerance {
+					t.Fatalf("tolerance wrong. want=%g, got=%g", test.expectedTolerance, problem.Tolerance)
+				}
+				if problem.TimeLimit != test.expectedTimeLimit {
+					t.Fatalf("time limit wrong. want=%s, got=%s", test.expectedTimeLimit, problem.TimeLimit)
+				}
+				if problem.MemoryLimit != test.expectedMemoryLimit {
+					t.Fatalf("memory limit wrong. want=%d, got=%d", test.expectedMemoryLimit, problem.MemoryLimit)
+				}
+				samples := problem.Samples
 				if len(samples) != len(test.expectedSamples) {
 					t.Fatalf("length of samples wrong. want=%d, got=%d", len(test.expectedSamples), len(samples))
 				}
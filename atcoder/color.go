@@ -0,0 +1,35 @@
+package atcoder
+
+import "github.com/fatih/color"
+
+// modes telling when outputs are colored. 'auto' colors them only on terminals
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+func IsColorMode(mode string) bool {
+	for _, m := range ColorModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// palette creates colors following the color mode. an empty mode is the same as 'auto'.
+type palette string
+
+func (p palette) color(attribute color.Attribute) *color.Color {
+	c := color.New(attribute)
+	switch string(p) {
+	case ColorAlways:
+		c.EnableColor()
+	case ColorNever:
+		c.DisableColor()
+	}
+	return c
+}
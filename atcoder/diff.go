@@ -0,0 +1,382 @@
+package atcoder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+const (
+	DiffUnified = "unified"
+	DiffSide    = "side"
+	DiffPlain   = "plain"
+)
+
+var DiffModes = []string{DiffUnified, DiffSide, DiffPlain}
+
+func IsDiffMode(mode string) bool {
+	for _, m := range DiffModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+type DiffOption struct {
+	Mode     string
+	MaxLines int    // max number of lines shown for each of the input and the diff. unlimited if not positive
+	Color    string // when to color outputs. one of ColorModes, which is 'auto' if empty
+}
+
+const (
+	diffContextLines = 2
+	sideColumnWidth  = 40
+	// lines are aligned only if the table of the longest common subsequence fits in this number of cells
+	maxAlignmentCells = 1 << 22
+)
+
+const (
+	expectedColor = color.FgGreen
+	actualColor   = color.FgRed
+)
+
+// diffRow is a line of the expected output and a line of the actual output shown together.
+// numbers are line numbers in each output, which are 0 if the line is absent.
+type diffRow struct {
+	expectedNumber int
+	actualNumber   int
+	expected       *string
+	actual         *string
+}
+
+// number returns the line number shown for the row, which is the one in the expected output if exists.
+func (r diffRow) number() int {
+	if r.expected != nil {
+		return r.expectedNumber
+	}
+	return r.actualNumber
+}
+
+func (r diffRow) equal() bool {
+	return r.expected != nil && r.actual != nil && *r.expected == *r.actual
+}
+
+// writeDiff writes the difference between the expected output and the actual output line by line.
+// lines are aligned by the longest common subsequence, so that a missing or extra line does not make the following lines differ.
+func writeDiff(w io.Writer, expected, actual string, option DiffOption) {
+	if option.Mode == DiffPlain {
+		_, _ = fmt.Fprintln(w, "expected output:")
+		writeTruncated(w, expected, option.MaxLines)
+		_, _ = fmt.Fprintln(w, "actual output:")
+		writeTruncated(w, actual, option.MaxLines)
+		return
+	}
+
+	expectedLines, expectedNewline := splitLines(expected)
+	actualLines, actualNewline := splitLines(actual)
+	rows := diffRows(expectedLines, actualLines)
+	p := palette(option.Color)
+
+	_, _ = fmt.Fprintln(w, firstDifference(rows, expectedNewline, actualNewline))
+	lw := &limitedWriter{w: w, maxLines: option.MaxLines}
+	if option.Mode == DiffSide {
+		writeSideBySide(lw, rows, p)
+	} else {
+		writeUnified(lw, rows, p)
+	}
+	lw.finish()
+
+	if expectedNewline && !actualNewline {
+		_, _ = fmt.Fprintln(w, `\ no newline at end of actual output`)
+	}
+	if !expectedNewline && actualNewline {
+		_, _ = fmt.Fprintln(w, `\ no newline at end of expected output`)
+	}
+}
+
+func writeUnified(w *limitedWriter, rows []diffRow, p palette) {
+	w.println("--- expected")
+	w.println("+++ actual")
+
+	shown := make([]bool, len(rows))
+	different := false
+	for i, row := range rows {
+		if row.equal() {
+			continue
+		}
+		different = true
+		for j := i - diffContextLines; j <= i+diffContextLines; j++ {
+			if 0 <= j && j < len(rows) {
+				shown[j] = true
+			}
+		}
+	}
+	// lines are all equal when only the newline at end of output differs. show the last lines in that case
+	if !different {
+		for j := len(rows) - 1; j >= 0 && j >= len(rows)-1-diffContextLines; j-- {
+			shown[j] = true
+		}
+	}
+
+	skipped := false
+	for i, row := range rows {
+		if !shown[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			w.println("  ...")
+			skipped = false
+		}
+
+		if row.equal() {
+			w.println(fmt.Sprintf("  %4d  %s", row.expectedNumber, visualize(*row.expected)))
+			continue
+		}
+		expectedText, actualText := highlightTokens(row, p)
+		if row.expected != nil {
+			w.println(p.color(expectedColor).Sprintf("- %4d  ", row.expectedNumber) + expectedText)
+		}
+		if row.actual != nil {
+			w.println(p.color(actualColor).Sprintf("+ %4d  ", row.actualNumber) + actualText)
+		}
+	}
+	if skipped {
+		w.println("  ...")
+	}
+}
+
+func writeSideBySide(w *limitedWriter, rows []diffRow, p palette) {
+	width := 0
+	for _, row := range rows {
+		if row.expected != nil {
+			if l := utf8.RuneCountInString(visualize(*row.expected)); l > width {
+				width = l
+			}
+		}
+	}
+	if width > sideColumnWidth {
+		width = sideColumnWidth
+	}
+	if width < len("expected") {
+		width = len("expected")
+	}
+
+	w.println(fmt.Sprintf("  %4s  %-*s   %s", "", width, "expected", "actual"))
+	for _, row := range rows {
+		var expectedText, actualText string
+		if row.expected != nil {
+			expectedText = truncateRunes(visualize(*row.expected), width)
+		}
+		if row.actual != nil {
+			actualText = visualize(*row.actual)
+		}
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(expectedText))
+
+		if row.equal() {
+			w.println(fmt.Sprintf("  %4d  %s%s | %s", row.number(), expectedText, padding, actualText))
+		} else {
+			w.println(fmt.Sprintf("! %4d  %s%s ! %s", row.number(), p.color(expectedColor).Sprint(expectedText), padding, p.color(actualColor).Sprint(actualText)))
+		}
+	}
+}
+
+// diffRows aligns the lines of the outputs. lines between aligned lines are paired by their positions to compare tokens.
+func diffRows(expectedLines, actualLines []string) []diffRow {
+	var rows []diffRow
+	i, j := 0, 0
+	matches := append(alignLines(expectedLines, actualLines), [2]int{len(expectedLines), len(actualLines)})
+	for _, match := range matches {
+		for i < match[0] || j < match[1] {
+			row := diffRow{}
+			if i < match[0] {
+				row.expectedNumber, row.expected = i+1, &expectedLines[i]
+				i++
+			}
+			if j < match[1] {
+				row.actualNumber, row.actual = j+1, &actualLines[j]
+				j++
+			}
+			rows = append(rows, row)
+		}
+		if i < len(expectedLines) && j < len(actualLines) {
+			rows = append(rows, diffRow{expectedNumber: i + 1, actualNumber: j + 1, expected: &expectedLines[i], actual: &actualLines[j]})
+			i++
+			j++
+		}
+	}
+	return rows
+}
+
+// alignLines returns pairs of indices of equal lines in the longest common subsequence.
+// the common prefix and suffix are aligned first, and the rest is not aligned if it is too large.
+func alignLines(a, b []string) [][2]int {
+	var matches [][2]int
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		matches = append(matches, [2]int{prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	n, m := len(a)-prefix-suffix, len(b)-prefix-suffix
+	if n > 0 && m > 0 && n*m <= maxAlignmentCells {
+		// lcs[i][j] is the length of the longest common subsequence of a[prefix+i:] and b[prefix+j:] in the middle
+		lcs := make([][]int32, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if a[prefix+i] == b[prefix+j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		for i, j := 0, 0; i < n && j < m; {
+			switch {
+			case a[prefix+i] == b[prefix+j]:
+				matches = append(matches, [2]int{prefix + i, prefix + j})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				i++
+			default:
+				j++
+			}
+		}
+	}
+
+	for k := suffix; k > 0; k-- {
+		matches = append(matches, [2]int{len(a) - k, len(b) - k})
+	}
+	return matches
+}
+
+func firstDifference(rows []diffRow, expectedNewline, actualNewline bool) string {
+	for _, row := range rows {
+		if row.equal() {
+			continue
+		}
+		if row.expected == nil {
+			return fmt.Sprintf("first difference at line %d: extra line in actual output", row.actualNumber)
+		}
+		if row.actual == nil {
+			return fmt.Sprintf("first difference at line %d: line missing in actual output", row.expectedNumber)
+		}
+
+		expectedTokens := strings.Fields(*row.expected)
+		actualTokens := strings.Fields(*row.actual)
+		for i := 0; i < len(expectedTokens) && i < len(actualTokens); i++ {
+			if expectedTokens[i] != actualTokens[i] {
+				return fmt.Sprintf("first difference at line %d, token %d: expected %q, got %q", row.expectedNumber, i+1, expectedTokens[i], actualTokens[i])
+			}
+		}
+		if len(expectedTokens) != len(actualTokens) {
+			return fmt.Sprintf("first difference at line %d: expected %d tokens, got %d tokens", row.expectedNumber, len(expectedTokens), len(actualTokens))
+		}
+		return fmt.Sprintf("first difference at line %d: whitespaces differ", row.expectedNumber)
+	}
+
+	if expectedNewline != actualNewline {
+		return "first difference: newline at end of output"
+	}
+	return "no difference found line by line"
+}
+
+// highlightTokens colors tokens which differ from the token at the same position of the other line.
+func highlightTokens(row diffRow, p palette) (string, string) {
+	if row.expected == nil || row.actual == nil {
+		var expectedText, actualText string
+		if row.expected != nil {
+			expectedText = p.color(expectedColor).Sprint(visualize(*row.expected))
+		}
+		if row.actual != nil {
+			actualText = p.color(actualColor).Sprint(visualize(*row.actual))
+		}
+		return expectedText, actualText
+	}
+
+	expectedTokens := strings.Split(visualize(*row.expected), " ")
+	actualTokens := strings.Split(visualize(*row.actual), " ")
+	highlight := func(tokens, others []string, c *color.Color) string {
+		highlighted := make([]string, len(tokens))
+		for i, token := range tokens {
+			if i < len(others) && token == others[i] {
+				highlighted[i] = token
+			} else {
+				highlighted[i] = c.Sprint(token)
+			}
+		}
+		return strings.Join(highlighted, " ")
+	}
+
+	return highlight(expectedTokens, actualTokens, p.color(expectedColor)), highlight(actualTokens, expectedTokens, p.color(actualColor))
+}
+
+// visualize makes trailing whitespaces and carriage returns visible.
+func visualize(line string) string {
+	trimmed := strings.TrimRight(line, " \t\r")
+	trailing := line[len(trimmed):]
+	replacer := strings.NewReplacer(" ", "␣", "\t", "→", "\r", "␍")
+	return trimmed + replacer.Replace(trailing)
+}
+
+func splitLines(s string) ([]string, bool) {
+	if s == "" {
+		return nil, true
+	}
+	hasNewline := strings.HasSuffix(s, "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n"), hasNewline
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
+
+// writeTruncated writes s, omitting lines after maxLines.
+func writeTruncated(w io.Writer, s string, maxLines int) {
+	lines, _ := splitLines(s)
+	if maxLines <= 0 || len(lines) <= maxLines {
+		_, _ = fmt.Fprint(w, s)
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, strings.Join(lines[:maxLines], "\n"))
+	_, _ = fmt.Fprintf(w, "... (%d more lines)\n", len(lines)-maxLines)
+}
+
+type limitedWriter struct {
+	w        io.Writer
+	maxLines int
+	lines    int
+}
+
+func (l *limitedWriter) println(line string) {
+	l.lines++
+	if l.maxLines > 0 && l.lines > l.maxLines {
+		return
+	}
+	_, _ = fmt.Fprintln(l.w, line)
+}
+
+func (l *limitedWriter) finish() {
+	if l.maxLines > 0 && l.lines > l.maxLines {
+		_, _ = fmt.Fprintf(l.w, "... (%d more lines)\n", l.lines-l.maxLines)
+	}
+}
@@ -0,0 +1,143 @@
+package atcoder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_writeDiff(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputExpected  string
+		inputActual    string
+		inputOption    DiffOption
+		expectedOutput string
+	}{
+		{
+			name:           "unified-token differs",
+			inputExpected:  "1\n2 3 4\n5\n",
+			inputActual:    "1\n2 9 4\n5\n",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "first difference at line 2, token 2: expected \"3\", got \"9\"\n--- expected\n+++ actual\n     1  1\n-    2  2 3 4\n+    2  2 9 4\n     3  5\n",
+		},
+		{
+			name:           "unified-line missing",
+			inputExpected:  "1\n2\n",
+			inputActual:    "1\n",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "first difference at line 2: line missing in actual output\n--- expected\n+++ actual\n     1  1\n-    2  2\n",
+		},
+		{
+			name:           "unified-line inserted in the middle",
+			inputExpected:  "1\n2\n3\n4\n5\n6\n",
+			inputActual:    "1\n2\n9\n3\n4\n5\n6\n",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "first difference at line 3: extra line in actual output\n--- expected\n+++ actual\n     1  1\n     2  2\n+    3  9\n     3  3\n     4  4\n  ...\n",
+		},
+		{
+			name:           "unified-line removed in the middle",
+			inputExpected:  "a\nb\nc\nd\ne\n",
+			inputActual:    "a\nc\nd\nx\n",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "first difference at line 2: line missing in actual output\n--- expected\n+++ actual\n     1  a\n-    2  b\n     3  c\n     4  d\n-    5  e\n+    4  x\n",
+		},
+		{
+			name:           "side-line inserted in the middle",
+			inputExpected:  "1\n2\n",
+			inputActual:    "1\n3\n2\n",
+			inputOption:    DiffOption{Mode: DiffSide},
+			expectedOutput: "        expected   actual\n     1  1        | 1\n!    2           ! 3\n     2  2        | 2\n",
+		},
+		{
+			name:           "unified-context omitted",
+			inputExpected:  "1\n2\n3\n4\n5\n6\n",
+			inputActual:    "1\n2\n3\n4\n5\n7\n",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "--- expected\n+++ actual\n  ...\n     4  4\n     5  5\n-    6  6\n+    6  7\n",
+		},
+		{
+			name:           "unified-trailing whitespace",
+			inputExpected:  "1 2\n",
+			inputActual:    "1 2 \n",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "first difference at line 1: whitespaces differ\n--- expected\n+++ actual\n-    1  1 2\n+    1  1 2␣\n",
+		},
+		{
+			name:           "unified-no newline at end",
+			inputExpected:  "1\n",
+			inputActual:    "1",
+			inputOption:    DiffOption{Mode: DiffUnified},
+			expectedOutput: "first difference: newline at end of output\n--- expected\n+++ actual\n     1  1\n\\ no newline at end of actual output\n",
+		},
+		{
+			name:           "unified-truncated",
+			inputExpected:  "1\n2\n3\n",
+			inputActual:    "4\n5\n6\n",
+			inputOption:    DiffOption{Mode: DiffUnified, MaxLines: 4},
+			expectedOutput: "-    1  1\n+    1  4\n... (4 more lines)\n",
+		},
+		{
+			name:           "unified-colored",
+			inputExpected:  "1\n",
+			inputActual:    "2\n",
+			inputOption:    DiffOption{Mode: DiffUnified, Color: ColorAlways},
+			expectedOutput: "\x1b[32m-    1  \x1b[0m\x1b[32m1\x1b[0m\n\x1b[31m+    1  \x1b[0m\x1b[31m2\x1b[0m\n",
+		},
+		{
+			name:           "side",
+			inputExpected:  "1\n23\n",
+			inputActual:    "1\n45\n",
+			inputOption:    DiffOption{Mode: DiffSide},
+			expectedOutput: "        expected   actual\n     1  1        | 1\n!    2  23       ! 45\n",
+		},
+		{
+			name:           "plain",
+			inputExpected:  "1\n2\n3\n",
+			inputActual:    "4\n",
+			inputOption:    DiffOption{Mode: DiffPlain, MaxLines: 2},
+			expectedOutput: "expected output:\n1\n2\n... (1 more lines)\nactual output:\n4\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writeDiff(&out, test.inputExpected, test.inputActual, test.inputOption)
+			if !strings.Contains(out.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", out.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func Test_visualize(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputLine string
+		expected  string
+	}{
+		{
+			name:      "no trailing whitespace",
+			inputLine: "1 2",
+			expected:  "1 2",
+		},
+		{
+			name:      "trailing whitespaces",
+			inputLine: "1 2 \t",
+			expected:  "1 2␣→",
+		},
+		{
+			name:      "carriage return",
+			inputLine: "1 2\r",
+			expected:  "1 2␍",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := visualize(test.inputLine)
+			if actual != test.expected {
+				t.Fatalf("line wrong. want=%q, got=%q", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package atcoder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	FormatHuman = "human"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatTAP   = "tap"
+)
+
+var FormatNames = []string{FormatHuman, FormatJSON, FormatJUnit, FormatTAP}
+
+// CheckSettings describes how the samples are checked, which is passed to Formatter before results.
+type CheckSettings struct {
+	Judge               string
+	Samples             int
+	TimeLimit           time.Duration
+	MemoryLimit         int64
+	MemoryLimitEnforced bool
+}
+
+// Formatter reports results of samples.
+// Sample is called in order of samples as soon as each result is available, and End is called after all of them.
+type Formatter interface {
+	Begin(settings CheckSettings)
+	Sample(index int, sample Sample, result *SampleResult)
+	End()
+}
+
+func IsFormat(name string) bool {
+	for _, n := range FormatNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func NewFormatter(name string, w io.Writer, diffOption DiffOption) (Formatter, error) {
+	switch name {
+	case FormatHuman:
+		return NewHumanFormatter(w, diffOption), nil
+	case FormatJSON:
+		return &JSONFormatter{w: w}, nil
+	case FormatJUnit:
+		return &JUnitFormatter{w: w}, nil
+	case FormatTAP:
+		return &TAPFormatter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown format '%s'. available formats: %s", name, strings.Join(FormatNames, ", "))
+	}
+}
+
+// HumanFormatter writes colored results with inputs and diffs of failed samples.
+type HumanFormatter struct {
+	w          io.Writer
+	diffOption DiffOption
+	settings   CheckSettings
+}
+
+func NewHumanFormatter(w io.Writer, diffOption DiffOption) *HumanFormatter {
+	return &HumanFormatter{w: w, diffOption: diffOption}
+}
+
+func (f *HumanFormatter) Begin(settings CheckSettings) {
+	f.settings = settings
+}
+
+func (f *HumanFormatter) Sample(index int, sample Sample, result *SampleResult) {
+	_, _ = fmt.Fprintf(f.w, "%s: ", sample.Label(index))
+	switch result.Verdict {
+	case VerdictSuccess:
+		_, _ = palette(f.diffOption.Color).color(color.FgGreen).Fprint(f.w, VerdictSuccess)
+		_, _ = fmt.Fprintf(f.w, " (%s) %s\n", f.settings.Judge, formatUsage(result))
+	case VerdictFailure:
+		_, _ = palette(f.diffOption.Color).color(color.FgRed).Fprint(f.w, VerdictFailure)
+		_, _ = fmt.Fprintf(f.w, " (%s) %s\n", f.settings.Judge, formatUsage(result))
+		_, _ = fmt.Fprintln(f.w, "input:")
+		writeTruncated(f.w, sample.Input, f.diffOption.MaxLines)
+		writeDiff(f.w, sample.Output, result.Output, f.diffOption)
+		if result.Message != "" {
+			_, _ = fmt.Fprintln(f.w, "judge message:")
+			_, _ = fmt.Fprintln(f.w, result.Message)
+		}
+	case VerdictTLE:
+		_, _ = palette(f.diffOption.Color).color(color.FgYellow).Fprint(f.w, VerdictTLE)
+		_, _ = fmt.Fprintf(f.w, " %s\n", formatUsage(result))
+		_, _ = fmt.Fprintf(f.w, "time limit: %d ms\n", f.settings.TimeLimit.Milliseconds())
+		_, _ = fmt.Fprintln(f.w, "input:")
+		writeTruncated(f.w, sample.Input, f.diffOption.MaxLines)
+	case VerdictMLE:
+		_, _ = palette(f.diffOption.Color).color(color.FgYellow).Fprint(f.w, VerdictMLE)
+		_, _ = fmt.Fprintf(f.w, " %s\n", formatUsage(result))
+		_, _ = fmt.Fprintf(f.w, "memory limit: %d KB\n", f.settings.MemoryLimit/1024)
+		_, _ = fmt.Fprintln(f.w, "input:")
+		writeTruncated(f.w, sample.Input, f.diffOption.MaxLines)
+	case VerdictRE:
+		_, _ = palette(f.diffOption.Color).color(color.FgYellow).Fprint(f.w, VerdictRE)
+		if result.Signal != "" {
+			_, _ = fmt.Fprintf(f.w, " (%s) %s\n", result.Signal, formatUsage(result))
+		} else {
+			_, _ = fmt.Fprintf(f.w, " (exit code %d) %s\n", result.ExitCode, formatUsage(result))
+		}
+		if f.settings.MemoryLimitEnforced {
+			_, _ = fmt.Fprintf(f.w, "address space is limited to %d KB by rlimit. the error may be caused by exceeding it.\n", f.settings.MemoryLimit/1024)
+		}
+		if result.Stderr != "" {
+			_, _ = fmt.Fprintln(f.w, "stderr:")
+			_, _ = fmt.Fprint(f.w, tailLines(result.Stderr, stderrTailLines))
+		}
+		_, _ = fmt.Fprintln(f.w, "input:")
+		writeTruncated(f.w, sample.Input, f.diffOption.MaxLines)
+	default:
+		_, _ = palette(f.diffOption.Color).color(color.FgRed).Fprintln(f.w, VerdictError)
+		_, _ = fmt.Fprintln(f.w, result.Message)
+	}
+}
+
+func (f *HumanFormatter) End() {}
+
+func formatUsage(result *SampleResult) string {
+	return fmt.Sprintf("[time: %d ms, cpu: %d ms, memory: %d KB]", result.Time.Milliseconds(), result.CPUTime.Milliseconds(), result.Memory/1024)
+}
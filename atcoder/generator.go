@@ -0,0 +1,902 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Spec describes the format of random inputs by statements separated by ';' or newlines.
+// each statement prints its own line(s), and integers defined before can be used in sizes and ranges.
+//
+//	N int [1,2e5]              N on a line
+//	H W int [1,10]             H and W on a line
+//	A [N]int [-1e9,1e9]        N integers on a line
+//	B [H][W]int [0,N]          H lines of W integers
+//	S [N]char [a-z]            string of length N
+//	G [H][W]char [.#]          H lines of W characters
+//	edges tree N               N-1 edges 'u v' of a random tree of vertices 1..N
+//	edges graph N M [1,1e9]    M edges 'u v w' of a random simple graph with weights
+//
+// lines starting with '#' are comments.
+type Spec struct {
+	statements []specStatement
+}
+
+type specStatement interface {
+	generate(r *rand.Rand, vars map[string]int64, b *strings.Builder) error
+	// lineCount returns the number of lines printed by the statement
+	lineCount(vars map[string]int64) (int64, error)
+	// check validates the lines printed by the statement, and defines integers like generate
+	check(lines []string, vars map[string]int64) error
+	// shrink rewrites the lines for the integers changed from old to vars.
+	// elements indexed by the integers in removed are removed, and the others are truncated to the new sizes
+	shrink(lines []string, old, vars map[string]int64, removed map[string]func(i int64) bool) ([]string, error)
+}
+
+var (
+	specValueRegexp = regexp.MustCompile(`^([A-Za-z_]\w*(?:\s+[A-Za-z_]\w*)*)\s+((?:\[[^\]\s]+\])*)(int|char)\s*(\[.*\])$`)
+	specEdgeRegexp  = regexp.MustCompile(`^([A-Za-z_]\w*)\s+(tree|graph)\s+([^\[]+?)\s*(\[.*\])?$`)
+	specDimRegexp   = regexp.MustCompile(`\[([^\]]+)\]`)
+	specTokenRegexp = regexp.MustCompile(`^\s*([A-Za-z_]\w*|[0-9][0-9eE.]*|[-+*])`)
+)
+
+var specKeywords = map[string]bool{"int": true, "char": true, "tree": true, "graph": true}
+
+// ParseSpec parses the spec of random inputs.
+func ParseSpec(text string) (*Spec, error) {
+	spec := &Spec{}
+	// names of integers defined so far, which can be referred by following statements
+	defined := map[string]bool{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, s := range strings.Split(line, ";") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			statement, err := parseSpecStatement(s, defined)
+			if err != nil {
+				return nil, fmt.Errorf("invalid spec '%s': %s", s, err)
+			}
+			spec.statements = append(spec.statements, statement)
+		}
+	}
+	if len(spec.statements) == 0 {
+		return nil, errors.New("spec is empty")
+	}
+	return spec, nil
+}
+
+// Generate returns the random input for the seed. the same input is returned for the same seed.
+func (s *Spec) Generate(seed int64) (string, error) {
+	r := rand.New(rand.NewSource(seed))
+	vars := map[string]int64{}
+	var b strings.Builder
+	for _, statement := range s.statements {
+		if err := statement.generate(r, vars, &b); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// Validate checks that the input follows the spec.
+func (s *Spec) Validate(input string) error {
+	_, _, err := s.split(input)
+	return err
+}
+
+// split splits the input into the lines of each statement, and returns them with the integers defined in the input.
+func (s *Spec) split(input string) ([][]string, map[string]int64, error) {
+	var lines []string
+	if input != "" {
+		lines = strings.Split(strings.TrimSuffix(input, "\n"), "\n")
+	}
+	vars := map[string]int64{}
+	parts := make([][]string, len(s.statements))
+	pos := int64(0)
+	for i, statement := range s.statements {
+		n, err := statement.lineCount(vars)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pos+n > int64(len(lines)) {
+			return nil, nil, fmt.Errorf("input has %d lines, but %d lines are expected at least", len(lines), pos+n)
+		}
+		parts[i] = lines[pos : pos+n]
+		if err := statement.check(parts[i], vars); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %s", pos+1, err)
+		}
+		pos += n
+	}
+	if pos < int64(len(lines)) {
+		return nil, nil, fmt.Errorf("input has %d lines, but %d lines are expected", len(lines), pos)
+	}
+	return parts, vars, nil
+}
+
+// integers returns the names of integers defined by the spec, and whether each of them is used as a size as it is.
+func (s *Spec) integers() ([]string, map[string]bool) {
+	var names []string
+	sizes := map[string]bool{}
+	for _, statement := range s.statements {
+		switch st := statement.(type) {
+		case *intStatement:
+			if len(st.dims) == 0 {
+				names = append(names, st.names...)
+			}
+			for _, dim := range st.dims {
+				sizes[strings.TrimSpace(dim)] = true
+			}
+		case *charStatement:
+			for _, dim := range st.dims {
+				sizes[strings.TrimSpace(dim)] = true
+			}
+		case *edgeStatement:
+			sizes[strings.TrimSpace(st.vertices)] = true
+			sizes[strings.TrimSpace(st.edges)] = true
+		}
+	}
+	return names, sizes
+}
+
+// shrink returns the input whose integer of the name is changed to the value.
+// if the integer is a size, the elements for which removed returns true are removed.
+func (s *Spec) shrink(parts [][]string, old map[string]int64, name string, value int64, removed func(i int64) bool) (string, error) {
+	vars := map[string]int64{}
+	for k, v := range old {
+		vars[k] = v
+	}
+	vars[name] = value
+	removes := map[string]func(i int64) bool{name: removed}
+
+	shrunk := make([][]string, len(parts))
+	for i, statement := range s.statements {
+		lines, err := statement.shrink(parts[i], old, vars, removes)
+		if err != nil {
+			return "", err
+		}
+		shrunk[i] = lines
+	}
+	// integers are printed again because the number of edges can change by removing vertices
+	for i, statement := range s.statements {
+		if st, ok := statement.(*intStatement); ok && len(st.dims) == 0 {
+			lines, err := st.shrink(parts[i], old, vars, removes)
+			if err != nil {
+				return "", err
+			}
+			shrunk[i] = lines
+		}
+	}
+
+	var b strings.Builder
+	for _, lines := range shrunk {
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+func parseSpecStatement(s string, defined map[string]bool) (specStatement, error) {
+	if m := specEdgeRegexp.FindStringSubmatch(s); m != nil {
+		statement := &edgeStatement{name: m[1], kind: m[2]}
+		args := strings.Fields(m[3])
+		switch {
+		case statement.kind == "tree" && len(args) == 1:
+			statement.vertices = args[0]
+		case statement.kind == "graph" && len(args) == 2:
+			statement.vertices, statement.edges = args[0], args[1]
+		case statement.kind == "tree":
+			return nil, errors.New("tree takes the number of vertices. e.g.) edges tree N")
+		default:
+			return nil, errors.New("graph takes the numbers of vertices and edges. e.g.) edges graph N M")
+		}
+		for _, arg := range args {
+			if err := checkSpecExpr(arg, defined); err != nil {
+				return nil, err
+			}
+		}
+		if m[4] != "" {
+			lo, hi, err := parseSpecRange(m[4], defined)
+			if err != nil {
+				return nil, err
+			}
+			statement.weighted, statement.lo, statement.hi = true, lo, hi
+		}
+		return statement, nil
+	}
+
+	m := specValueRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, errors.New("statement should be like 'N int [1,10]', 'A [N]int [1,10]', 'S [N]char [a-z]' or 'edges tree N'")
+	}
+	names := strings.Fields(m[1])
+	for _, name := range names {
+		if specKeywords[name] {
+			return nil, fmt.Errorf("'%s' cannot be used as a name", name)
+		}
+		if defined[name] {
+			return nil, fmt.Errorf("'%s' is defined twice", name)
+		}
+	}
+	var dims []string
+	for _, dim := range specDimRegexp.FindAllStringSubmatch(m[2], -1) {
+		if err := checkSpecExpr(dim[1], defined); err != nil {
+			return nil, err
+		}
+		dims = append(dims, dim[1])
+	}
+	if len(dims) > 2 {
+		return nil, errors.New("arrays should have at most 2 dimensions")
+	}
+	if len(dims) > 0 && len(names) > 1 {
+		return nil, errors.New("arrays should be defined one by one")
+	}
+
+	if m[3] == "char" {
+		chars, err := parseSpecCharClass(m[4])
+		if err != nil {
+			return nil, err
+		}
+		return &charStatement{name: names[0], dims: dims, chars: chars}, nil
+	}
+
+	lo, hi, err := parseSpecRange(m[4], defined)
+	if err != nil {
+		return nil, err
+	}
+	// only scalar integers can be referred by following statements
+	if len(dims) == 0 {
+		for _, name := range names {
+			defined[name] = true
+		}
+	}
+	return &intStatement{names: names, dims: dims, lo: lo, hi: hi}, nil
+}
+
+// parseSpecRange parses the inclusive range like [1,2e5].
+func parseSpecRange(s string, defined map[string]bool) (string, string, error) {
+	bounds := strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"), ",")
+	if len(bounds) != 2 {
+		return "", "", fmt.Errorf("range should be like [1,10]: %s", s)
+	}
+	lo, hi := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+	for _, bound := range []string{lo, hi} {
+		if err := checkSpecExpr(bound, defined); err != nil {
+			return "", "", err
+		}
+	}
+	return lo, hi, nil
+}
+
+// parseSpecCharClass parses the characters like [a-z] or [.#].
+func parseSpecCharClass(s string) ([]rune, error) {
+	class := []rune(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	var chars []rune
+	for i := 0; i < len(class); i++ {
+		if i+2 < len(class) && class[i+1] == '-' {
+			if class[i] > class[i+2] {
+				return nil, fmt.Errorf("invalid range of characters: %s", string(class[i:i+3]))
+			}
+			for c := class[i]; c <= class[i+2]; c++ {
+				chars = append(chars, c)
+			}
+			i += 2
+			continue
+		}
+		chars = append(chars, class[i])
+	}
+	if len(chars) == 0 {
+		return nil, fmt.Errorf("characters should be like [a-z]: %s", s)
+	}
+	return chars, nil
+}
+
+// checkSpecExpr checks the syntax of the expression and that it refers only to defined integers.
+func checkSpecExpr(expr string, defined map[string]bool) error {
+	_, err := evalSpecExpr(expr, func(name string) (int64, bool) { return 1, defined[name] })
+	return err
+}
+
+// evalSpecExpr evaluates the expression of integers like 2e5, N-1 or 2*N with + - and *.
+func evalSpecExpr(expr string, lookup func(name string) (int64, bool)) (int64, error) {
+	var tokens []string
+	for rest := expr; strings.TrimSpace(rest) != ""; {
+		m := specTokenRegexp.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("invalid expression: %s", expr)
+		}
+		tokens = append(tokens, m[1])
+		rest = rest[len(m[0]):]
+	}
+
+	pos := 0
+	operand := func() (int64, error) {
+		if pos >= len(tokens) {
+			return 0, fmt.Errorf("invalid expression: %s", expr)
+		}
+		token := tokens[pos]
+		pos++
+		if c := token[0]; c >= '0' && c <= '9' {
+			return parseSpecNumber(token)
+		}
+		if c := token[0]; c == '+' || c == '-' || c == '*' {
+			return 0, fmt.Errorf("invalid expression: %s", expr)
+		}
+		value, ok := lookup(token)
+		if !ok {
+			return 0, fmt.Errorf("'%s' is not defined as an integer before", token)
+		}
+		return value, nil
+	}
+	term := func() (int64, error) {
+		value, err := operand()
+		if err != nil {
+			return 0, err
+		}
+		for pos < len(tokens) && tokens[pos] == "*" {
+			pos++
+			factor, err := operand()
+			if err != nil {
+				return 0, err
+			}
+			value *= factor
+		}
+		return value, nil
+	}
+
+	sign := int64(1)
+	if pos < len(tokens) && (tokens[pos] == "-" || tokens[pos] == "+") {
+		if tokens[pos] == "-" {
+			sign = -1
+		}
+		pos++
+	}
+	value, err := term()
+	if err != nil {
+		return 0, err
+	}
+	value *= sign
+	for pos < len(tokens) {
+		op := tokens[pos]
+		if op != "+" && op != "-" {
+			return 0, fmt.Errorf("invalid expression: %s", expr)
+		}
+		pos++
+		t, err := term()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			value += t
+		} else {
+			value -= t
+		}
+	}
+	return value, nil
+}
+
+// parseSpecNumber parses integers which can be written like 2e5.
+func parseSpecNumber(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid integer: %s", s)
+	}
+	return int64(f), nil
+}
+
+func evalSpecVars(expr string, vars map[string]int64) (int64, error) {
+	return evalSpecExpr(expr, func(name string) (int64, bool) {
+		value, ok := vars[name]
+		return value, ok
+	})
+}
+
+// evalSpecDims returns the sizes of the array, which are rows and columns for 2 dimensions.
+func evalSpecDims(name string, dims []string, vars map[string]int64) (int64, int64, error) {
+	sizes := []int64{1, 1}
+	for i, dim := range dims {
+		size, err := evalSpecVars(dim, vars)
+		if err != nil {
+			return 0, 0, err
+		}
+		if size < 0 {
+			return 0, 0, fmt.Errorf("size of %s should not be negative: %s = %d", name, dim, size)
+		}
+		sizes[2-len(dims)+i] = size
+	}
+	return sizes[0], sizes[1], nil
+}
+
+// shrinkIndices returns the indices of elements kept when the size given by the expression changes from oldSize to newSize.
+// the elements for which removed of the size returns true are removed, and the others are truncated.
+func shrinkIndices(expr string, oldSize, newSize int64, removed map[string]func(i int64) bool) ([]int64, error) {
+	f := removed[strings.TrimSpace(expr)]
+	var kept []int64
+	for i := int64(0); i < oldSize; i++ {
+		if (f != nil && !f(i)) || (f == nil && i < newSize) {
+			kept = append(kept, i)
+		}
+	}
+	if int64(len(kept)) != newSize {
+		return nil, fmt.Errorf("size %s cannot change from %d to %d by removing elements", expr, oldSize, newSize)
+	}
+	return kept, nil
+}
+
+// shrinkGrid shrinks the rows and the columns of the array. split splits a row into elements, which are joined by sep.
+func shrinkGrid(name string, dims []string, lines []string, old, vars map[string]int64, removed map[string]func(i int64) bool, split func(line string) []string, sep string) ([]string, error) {
+	oldRows, oldCols, err := evalSpecDims(name, dims, old)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols, err := evalSpecDims(name, dims, vars)
+	if err != nil {
+		return nil, err
+	}
+	// sizes of 1 dimensional arrays are given to columns
+	rowExpr, colExpr := "", ""
+	if len(dims) == 2 {
+		rowExpr, colExpr = dims[0], dims[1]
+	} else if len(dims) == 1 {
+		colExpr = dims[0]
+	}
+	keptRows, err := shrinkIndices(rowExpr, oldRows, rows, removed)
+	if err != nil {
+		return nil, err
+	}
+	keptCols, err := shrinkIndices(colExpr, oldCols, cols, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	shrunk := make([]string, len(keptRows))
+	for i, row := range keptRows {
+		elements := split(lines[row])
+		kept := make([]string, len(keptCols))
+		for j, col := range keptCols {
+			kept[j] = elements[col]
+		}
+		shrunk[i] = strings.Join(kept, sep)
+	}
+	return shrunk, nil
+}
+
+// parseSpecInts parses the integers of the line which should be in [lo, hi].
+func parseSpecInts(line string, count, lo, hi int64, name string) ([]int64, error) {
+	tokens := strings.Fields(line)
+	if int64(len(tokens)) != count {
+		return nil, fmt.Errorf("%d integers are expected for %s, but got %d", count, name, len(tokens))
+	}
+	values := make([]int64, len(tokens))
+	for i, token := range tokens {
+		value, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s should be integers: %s", name, token)
+		}
+		if value < lo || value > hi {
+			return nil, fmt.Errorf("%s should be in [%d,%d]: %d", name, lo, hi, value)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// randomInt returns a random integer in [lo, hi].
+func randomInt(r *rand.Rand, lo, hi int64) int64 {
+	span := uint64(hi) - uint64(lo) + 1
+	if span == 0 {
+		return int64(r.Uint64())
+	}
+	return lo + int64(r.Uint64()%span)
+}
+
+type intStatement struct {
+	names  []string
+	dims   []string
+	lo, hi string
+}
+
+func (s *intStatement) generate(r *rand.Rand, vars map[string]int64, b *strings.Builder) error {
+	lo, err := evalSpecVars(s.lo, vars)
+	if err != nil {
+		return err
+	}
+	hi, err := evalSpecVars(s.hi, vars)
+	if err != nil {
+		return err
+	}
+	if lo > hi {
+		return fmt.Errorf("range of %s is empty: [%d,%d]", strings.Join(s.names, " "), lo, hi)
+	}
+
+	if len(s.dims) == 0 {
+		values := make([]string, len(s.names))
+		for i, name := range s.names {
+			value := randomInt(r, lo, hi)
+			vars[name] = value
+			values[i] = strconv.FormatInt(value, 10)
+		}
+		b.WriteString(strings.Join(values, " "))
+		b.WriteString("\n")
+		return nil
+	}
+
+	rows, cols, err := evalSpecDims(s.names[0], s.dims, vars)
+	if err != nil {
+		return err
+	}
+	for i := int64(0); i < rows; i++ {
+		for j := int64(0); j < cols; j++ {
+			if j > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(strconv.FormatInt(randomInt(r, lo, hi), 10))
+		}
+		b.WriteString("\n")
+	}
+	return nil
+}
+
+func (s *intStatement) lineCount(vars map[string]int64) (int64, error) {
+	if len(s.dims) == 0 {
+		return 1, nil
+	}
+	rows, _, err := evalSpecDims(s.names[0], s.dims, vars)
+	return rows, err
+}
+
+func (s *intStatement) check(lines []string, vars map[string]int64) error {
+	lo, err := evalSpecVars(s.lo, vars)
+	if err != nil {
+		return err
+	}
+	hi, err := evalSpecVars(s.hi, vars)
+	if err != nil {
+		return err
+	}
+
+	if len(s.dims) == 0 {
+		values, err := parseSpecInts(lines[0], int64(len(s.names)), lo, hi, strings.Join(s.names, " "))
+		if err != nil {
+			return err
+		}
+		for i, name := range s.names {
+			vars[name] = values[i]
+		}
+		return nil
+	}
+
+	_, cols, err := evalSpecDims(s.names[0], s.dims, vars)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := parseSpecInts(line, cols, lo, hi, s.names[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *intStatement) shrink(lines []string, old, vars map[string]int64, removed map[string]func(i int64) bool) ([]string, error) {
+	if len(s.dims) == 0 {
+		values := make([]string, len(s.names))
+		for i, name := range s.names {
+			values[i] = strconv.FormatInt(vars[name], 10)
+		}
+		return []string{strings.Join(values, " ")}, nil
+	}
+	return shrinkGrid(s.names[0], s.dims, lines, old, vars, removed, strings.Fields, " ")
+}
+
+type charStatement struct {
+	name  string
+	dims  []string
+	chars []rune
+}
+
+func (s *charStatement) generate(r *rand.Rand, vars map[string]int64, b *strings.Builder) error {
+	rows, cols, err := evalSpecDims(s.name, s.dims, vars)
+	if err != nil {
+		return err
+	}
+	for i := int64(0); i < rows; i++ {
+		for j := int64(0); j < cols; j++ {
+			b.WriteRune(s.chars[r.Intn(len(s.chars))])
+		}
+		b.WriteString("\n")
+	}
+	return nil
+}
+
+func (s *charStatement) lineCount(vars map[string]int64) (int64, error) {
+	rows, _, err := evalSpecDims(s.name, s.dims, vars)
+	return rows, err
+}
+
+func (s *charStatement) check(lines []string, vars map[string]int64) error {
+	_, cols, err := evalSpecDims(s.name, s.dims, vars)
+	if err != nil {
+		return err
+	}
+	chars := map[rune]bool{}
+	for _, c := range s.chars {
+		chars[c] = true
+	}
+	for _, line := range lines {
+		runes := []rune(line)
+		if int64(len(runes)) != cols {
+			return fmt.Errorf("%d characters are expected for %s, but got %d", cols, s.name, len(runes))
+		}
+		for _, c := range runes {
+			if !chars[c] {
+				return fmt.Errorf("%s should consist of [%s]: %q", s.name, string(s.chars), c)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *charStatement) shrink(lines []string, old, vars map[string]int64, removed map[string]func(i int64) bool) ([]string, error) {
+	return shrinkGrid(s.name, s.dims, lines, old, vars, removed, func(line string) []string { return strings.Split(line, "") }, "")
+}
+
+type edgeStatement struct {
+	name     string
+	kind     string
+	vertices string
+	edges    string
+	weighted bool
+	lo, hi   string
+}
+
+func (s *edgeStatement) generate(r *rand.Rand, vars map[string]int64, b *strings.Builder) error {
+	n, err := evalSpecVars(s.vertices, vars)
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return fmt.Errorf("number of vertices of %s should be positive: %s = %d", s.name, s.vertices, n)
+	}
+	var lo, hi int64
+	if s.weighted {
+		if lo, err = evalSpecVars(s.lo, vars); err != nil {
+			return err
+		}
+		if hi, err = evalSpecVars(s.hi, vars); err != nil {
+			return err
+		}
+		if lo > hi {
+			return fmt.Errorf("range of weights of %s is empty: [%d,%d]", s.name, lo, hi)
+		}
+	}
+
+	var edges [][2]int64
+	if s.kind == "tree" {
+		edges = randomTree(r, n)
+	} else {
+		m, err := evalSpecVars(s.edges, vars)
+		if err != nil {
+			return err
+		}
+		if m < 0 || m > n*(n-1)/2 {
+			return fmt.Errorf("number of edges of %s should be in [0,%d] for a simple graph: %s = %d", s.name, n*(n-1)/2, s.edges, m)
+		}
+		edges = randomGraph(r, n, m)
+	}
+
+	for _, e := range edges {
+		b.WriteString(strconv.FormatInt(e[0], 10))
+		b.WriteString(" ")
+		b.WriteString(strconv.FormatInt(e[1], 10))
+		if s.weighted {
+			b.WriteString(" ")
+			b.WriteString(strconv.FormatInt(randomInt(r, lo, hi), 10))
+		}
+		b.WriteString("\n")
+	}
+	return nil
+}
+
+func (s *edgeStatement) lineCount(vars map[string]int64) (int64, error) {
+	if s.kind == "tree" {
+		n, err := evalSpecVars(s.vertices, vars)
+		if err != nil {
+			return 0, err
+		}
+		if n < 1 {
+			return 0, fmt.Errorf("number of vertices of %s should be positive: %s = %d", s.name, s.vertices, n)
+		}
+		return n - 1, nil
+	}
+	m, err := evalSpecVars(s.edges, vars)
+	if err != nil {
+		return 0, err
+	}
+	if m < 0 {
+		return 0, fmt.Errorf("number of edges of %s should not be negative: %s = %d", s.name, s.edges, m)
+	}
+	return m, nil
+}
+
+func (s *edgeStatement) check(lines []string, vars map[string]int64) error {
+	n, err := evalSpecVars(s.vertices, vars)
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return fmt.Errorf("number of vertices of %s should be positive: %s = %d", s.name, s.vertices, n)
+	}
+	if m := int64(len(lines)); s.kind == "graph" && m > n*(n-1)/2 {
+		return fmt.Errorf("number of edges of %s should be in [0,%d] for a simple graph: %s = %d", s.name, n*(n-1)/2, s.edges, m)
+	}
+	lo, hi := int64(math.MinInt64), int64(math.MaxInt64)
+	if s.weighted {
+		if lo, err = evalSpecVars(s.lo, vars); err != nil {
+			return err
+		}
+		if hi, err = evalSpecVars(s.hi, vars); err != nil {
+			return err
+		}
+	}
+
+	// trees have no cycles, and simple graphs have no loops and multiple edges.
+	// parents are kept only for vertices in edges, whose number can be much smaller than n
+	parent := map[int64]int64{}
+	var find func(v int64) int64
+	find = func(v int64) int64 {
+		p, ok := parent[v]
+		if !ok || p == v {
+			return v
+		}
+		parent[v] = find(p)
+		return parent[v]
+	}
+	used := map[[2]int64]bool{}
+	// edges are 'u v', or 'u v w' if weighted
+	count := 2
+	if s.weighted {
+		count = 3
+	}
+	for _, line := range lines {
+		tokens := strings.Fields(line)
+		if len(tokens) != count {
+			return fmt.Errorf("%d integers are expected for edges of %s, but got %d", count, s.name, len(tokens))
+		}
+		vertices, err := parseSpecInts(strings.Join(tokens[:2], " "), 2, 1, n, "vertices of "+s.name)
+		if err != nil {
+			return err
+		}
+		if s.weighted {
+			if _, err := parseSpecInts(tokens[2], 1, lo, hi, "weights of "+s.name); err != nil {
+				return err
+			}
+		}
+		u, v := vertices[0], vertices[1]
+		if s.kind == "tree" {
+			if find(u) == find(v) {
+				return fmt.Errorf("edges of %s should not have a cycle: %s", s.name, line)
+			}
+			parent[find(u)] = find(v)
+			continue
+		}
+		if u == v || used[[2]int64{u, v}] || used[[2]int64{v, u}] {
+			return fmt.Errorf("%s should be a simple graph: %s", s.name, line)
+		}
+		used[[2]int64{u, v}] = true
+	}
+	return nil
+}
+
+func (s *edgeStatement) shrink(lines []string, old, vars map[string]int64, removed map[string]func(i int64) bool) ([]string, error) {
+	oldN, err := evalSpecVars(s.vertices, old)
+	if err != nil {
+		return nil, err
+	}
+	n, err := evalSpecVars(s.vertices, vars)
+	if err != nil {
+		return nil, err
+	}
+	keptVertices, err := shrinkIndices(s.vertices, oldN, n, removed)
+	if err != nil {
+		return nil, err
+	}
+	// vertices are numbered again from 1 in order
+	labels := map[int64]string{}
+	for i, v := range keptVertices {
+		labels[v+1] = strconv.Itoa(i + 1)
+	}
+
+	keptEdges := make([]int64, len(lines))
+	for i := range lines {
+		keptEdges[i] = int64(i)
+	}
+	if s.kind == "graph" {
+		oldM, err := evalSpecVars(s.edges, old)
+		if err != nil {
+			return nil, err
+		}
+		m, err := evalSpecVars(s.edges, vars)
+		if err != nil {
+			return nil, err
+		}
+		if keptEdges, err = shrinkIndices(s.edges, oldM, m, removed); err != nil {
+			return nil, err
+		}
+	}
+
+	var shrunk []string
+	for _, i := range keptEdges {
+		tokens := strings.Fields(lines[i])
+		u, _ := strconv.ParseInt(tokens[0], 10, 64)
+		v, _ := strconv.ParseInt(tokens[1], 10, 64)
+		lu, okU := labels[u]
+		lv, okV := labels[v]
+		if !okU || !okV {
+			continue
+		}
+		tokens[0], tokens[1] = lu, lv
+		shrunk = append(shrunk, strings.Join(tokens, " "))
+	}
+	// the number of edges given as an integer follows the removed edges
+	if name := strings.TrimSpace(s.edges); s.kind == "graph" && int64(len(shrunk)) != int64(len(keptEdges)) {
+		if _, ok := vars[name]; ok {
+			vars[name] = int64(len(shrunk))
+		}
+	}
+	return shrunk, nil
+}
+
+// randomTree returns edges of a random tree of vertices 1..n in random order.
+func randomTree(r *rand.Rand, n int64) [][2]int64 {
+	labels := r.Perm(int(n))
+	edges := make([][2]int64, 0, n-1)
+	for i := 1; i < int(n); i++ {
+		u, v := int64(labels[i]+1), int64(labels[r.Intn(i)]+1)
+		if r.Intn(2) == 0 {
+			u, v = v, u
+		}
+		edges = append(edges, [2]int64{u, v})
+	}
+	r.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	return edges
+}
+
+// randomGraph returns m edges of a random simple graph of vertices 1..n.
+func randomGraph(r *rand.Rand, n, m int64) [][2]int64 {
+	edges := make([][2]int64, 0, m)
+	// dense graphs are chosen from all pairs because random pairs rarely hit the rest
+	if 2*m > n*(n-1)/2 {
+		for u := int64(1); u <= n; u++ {
+			for v := u + 1; v <= n; v++ {
+				edges = append(edges, [2]int64{u, v})
+			}
+		}
+		r.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+		return edges[:m]
+	}
+
+	used := map[[2]int64]bool{}
+	for int64(len(edges)) < m {
+		u, v := randomInt(r, 1, n), randomInt(r, 1, n)
+		if u == v || used[[2]int64{u, v}] || used[[2]int64{v, u}] {
+			continue
+		}
+		used[[2]int64{u, v}] = true
+		edges = append(edges, [2]int64{u, v})
+	}
+	return edges
+}
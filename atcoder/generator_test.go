@@ -0,0 +1,326 @@
+package atcoder
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// parseInts parses whitespace-separated integers of the line.
+func parseInts(t *testing.T, line string) []int64 {
+	var values []int64
+	for _, token := range strings.Fields(line) {
+		n, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			t.Fatalf("not an integer: %s", token)
+		}
+		values = append(values, n)
+	}
+	return values
+}
+
+func TestSpec_Generate(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputSpec string
+		// check validates the generated lines
+		check func(t *testing.T, lines []string)
+	}{
+		{
+			name:      "integers and array",
+			inputSpec: "N int [1,2e1]; A [N]int [-1e9,1e9]",
+			check: func(t *testing.T, lines []string) {
+				n := parseInts(t, lines[0])[0]
+				if n < 1 || n > 20 {
+					t.Fatalf("N out of range: %d", n)
+				}
+				a := parseInts(t, lines[1])
+				if int64(len(a)) != n || len(lines) != 2 {
+					t.Fatalf("length of A wrong. want=%d, got=%d", n, len(a))
+				}
+				for _, v := range a {
+					if v < -1e9 || v > 1e9 {
+						t.Fatalf("A out of range: %d", v)
+					}
+				}
+			},
+		},
+		{
+			name:      "bounds referring to integers",
+			inputSpec: "N M int [2,5]\n# M queries\nQ [M][2]int [1,N]",
+			check: func(t *testing.T, lines []string) {
+				nm := parseInts(t, lines[0])
+				if len(lines) != 1+int(nm[1]) {
+					t.Fatalf("number of lines wrong. want=%d, got=%d", 1+nm[1], len(lines))
+				}
+				for _, line := range lines[1:] {
+					q := parseInts(t, line)
+					if len(q) != 2 || q[0] < 1 || q[0] > nm[0] || q[1] < 1 || q[1] > nm[0] {
+						t.Fatalf("query wrong: %s", line)
+					}
+				}
+			},
+		},
+		{
+			name:      "string and grid",
+			inputSpec: "H W int [1,5]; S [W]char [ab]; G [H][W-1]char [.#]",
+			check: func(t *testing.T, lines []string) {
+				hw := parseInts(t, lines[0])
+				if len(lines) != 2+int(hw[0]) {
+					t.Fatalf("number of lines wrong. want=%d, got=%d", 2+hw[0], len(lines))
+				}
+				if int64(len(lines[1])) != hw[1] || strings.Trim(lines[1], "ab") != "" {
+					t.Fatalf("string wrong: %q", lines[1])
+				}
+				for _, row := range lines[2:] {
+					if int64(len(row)) != hw[1]-1 || strings.Trim(row, ".#") != "" {
+						t.Fatalf("grid wrong: %q", row)
+					}
+				}
+			},
+		},
+		{
+			name:      "tree",
+			inputSpec: "N int [1,10]; edges tree N",
+			check: func(t *testing.T, lines []string) {
+				n := parseInts(t, lines[0])[0]
+				if int64(len(lines)) != n {
+					t.Fatalf("number of edges wrong. want=%d, got=%d", n-1, len(lines)-1)
+				}
+				// the edges are connected if every union merges two components
+				parent := make([]int64, n+1)
+				for i := range parent {
+					parent[i] = int64(i)
+				}
+				var find func(v int64) int64
+				find = func(v int64) int64 {
+					if parent[v] != v {
+						parent[v] = find(parent[v])
+					}
+					return parent[v]
+				}
+				for _, line := range lines[1:] {
+					e := parseInts(t, line)
+					u, v := find(e[0]), find(e[1])
+					if u == v {
+						t.Fatalf("edges have a cycle: %v", lines[1:])
+					}
+					parent[u] = v
+				}
+			},
+		},
+		{
+			name:      "weighted simple graph",
+			inputSpec: "N int [3,6]; M int [0,N]; edges graph N M [1,3]",
+			check: func(t *testing.T, lines []string) {
+				m := parseInts(t, lines[1])[0]
+				if len(lines) != 2+int(m) {
+					t.Fatalf("number of edges wrong. want=%d, got=%d", m, len(lines)-2)
+				}
+				used := map[[2]int64]bool{}
+				for _, line := range lines[2:] {
+					e := parseInts(t, line)
+					if len(e) != 3 || e[0] == e[1] || e[2] < 1 || e[2] > 3 || used[[2]int64{e[0], e[1]}] || used[[2]int64{e[1], e[0]}] {
+						t.Fatalf("edges wrong: %v", lines[2:])
+					}
+					used[[2]int64{e[0], e[1]}] = true
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ParseSpec(test.inputSpec)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			for seed := int64(1); seed <= 30; seed++ {
+				input, err := spec.Generate(seed)
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				again, _ := spec.Generate(seed)
+				if input != again {
+					t.Fatalf("input should be the same for the same seed. got:\n%s\n%s", input, again)
+				}
+				if err := spec.Validate(input); err != nil {
+					t.Fatalf("generated input should follow the spec. got: %s\n%s", err, input)
+				}
+				if test.check != nil {
+					test.check(t, strings.Split(strings.TrimSuffix(input, "\n"), "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestSpec_errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputSpec      string
+		expectedErrMsg string
+	}{
+		{
+			name:           "failure-empty",
+			inputSpec:      " ; \n# comment",
+			expectedErrMsg: "spec is empty",
+		},
+		{
+			name:           "failure-unknown type",
+			inputSpec:      "N float [1,10]",
+			expectedErrMsg: "statement should be like",
+		},
+		{
+			name:           "failure-undefined size",
+			inputSpec:      "A [N]int [1,10]; N int [1,10]",
+			expectedErrMsg: "'N' is not defined as an integer before",
+		},
+		{
+			name:           "failure-array as size",
+			inputSpec:      "A [2]int [1,10]; B [A]int [1,10]",
+			expectedErrMsg: "'A' is not defined as an integer before",
+		},
+		{
+			name:           "failure-invalid range",
+			inputSpec:      "N int [1.5,10]",
+			expectedErrMsg: "invalid integer: 1.5",
+		},
+		{
+			name:           "failure-defined twice",
+			inputSpec:      "N int [1,10]; N int [1,10]",
+			expectedErrMsg: "'N' is defined twice",
+		},
+		{
+			name:           "failure-tree without vertices",
+			inputSpec:      "edges tree",
+			expectedErrMsg: "statement should be like",
+		},
+		{
+			name:           "failure-empty range",
+			inputSpec:      "N int [1,3]; A [N]int [N+1,3]",
+			expectedErrMsg: "range of A is empty",
+		},
+		{
+			name:           "failure-too many edges",
+			inputSpec:      "N int [2,2]; edges graph N 2",
+			expectedErrMsg: "number of edges of edges should be in [0,1]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ParseSpec(test.inputSpec)
+			if err == nil {
+				_, err = spec.Generate(1)
+			}
+			if err == nil {
+				t.Fatal("err should not be nil. got: nil")
+			}
+			if !strings.Contains(err.Error(), test.expectedErrMsg) {
+				t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputSpec      string
+		inputInput     string
+		expectedErrMsg string
+	}{
+		{
+			name:       "success-integers and array",
+			inputSpec:  "N int [1,5]; A [N]int [-9,9]",
+			inputInput: "3\n-1 0 9\n",
+		},
+		{
+			name:       "success-empty array",
+			inputSpec:  "N int [0,5]; A [N]int [1,9]; S [N]char [a-z]",
+			inputInput: "0\n\n\n",
+		},
+		{
+			name:       "success-grid and weighted tree",
+			inputSpec:  "H W int [1,5]; G [H][W]char [.#]; edges tree H [1,9]",
+			inputInput: "2 3\n.#.\n###\n2 1 9\n",
+		},
+		{
+			name:           "failure-length of array",
+			inputSpec:      "N int [1,5]; A [N]int [1,9]",
+			inputInput:     "3\n1 2\n",
+			expectedErrMsg: "line 2: 3 integers are expected for A, but got 2",
+		},
+		{
+			name:           "failure-out of range",
+			inputSpec:      "N int [1,5]; A [N]int [1,N]",
+			inputInput:     "2\n1 3\n",
+			expectedErrMsg: "A should be in [1,2]: 3",
+		},
+		{
+			name:           "failure-character",
+			inputSpec:      "S [3]char [ab]",
+			inputInput:     "abc\n",
+			expectedErrMsg: "S should consist of [ab]: 'c'",
+		},
+		{
+			name:           "failure-missing lines",
+			inputSpec:      "H int [1,5]; G [H][2]char [.#]",
+			inputInput:     "3\n..\n",
+			expectedErrMsg: "input has 2 lines, but 4 lines are expected at least",
+		},
+		{
+			name:           "failure-extra lines",
+			inputSpec:      "N int [1,5]",
+			inputInput:     "3\n4\n",
+			expectedErrMsg: "input has 2 lines, but 1 lines are expected",
+		},
+		{
+			name:           "failure-cycle of tree",
+			inputSpec:      "N int [1,5]; edges tree N",
+			inputInput:     "4\n1 2\n2 1\n3 4\n",
+			expectedErrMsg: "edges of edges should not have a cycle: 2 1",
+		},
+		{
+			name:           "failure-negative number of vertices",
+			inputSpec:      "N int [-5,5]; edges graph N 0",
+			inputInput:     "-3\n",
+			expectedErrMsg: "number of vertices of edges should be positive: N = -3",
+		},
+		{
+			name:           "failure-too many edges",
+			inputSpec:      "N int [1,5]; M int [0,9]; edges graph N M",
+			inputInput:     "2\n2\n1 2\n2 1\n",
+			expectedErrMsg: "number of edges of edges should be in [0,1]",
+		},
+		{
+			name:           "failure-multiple edges",
+			inputSpec:      "N M int [1,5]; edges graph N M",
+			inputInput:     "3 2\n1 2\n2 1\n",
+			expectedErrMsg: "edges should be a simple graph: 2 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := ParseSpec(test.inputSpec)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			err = spec.Validate(test.inputInput)
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("err should not be nil. got: nil")
+			}
+			if !strings.Contains(err.Error(), test.expectedErrMsg) {
+				t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package atcoder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	JudgeExact      = "exact"
+	JudgeWhitespace = "whitespace"
+	JudgeUnordered  = "unordered"
+	JudgeFloat      = "float"
+	JudgeNoCase     = "nocase"
+)
+
+var JudgeNames = []string{JudgeExact, JudgeWhitespace, JudgeUnordered, JudgeFloat, JudgeNoCase}
+
+type Judge interface {
+	Name() string
+	Judge(sample Sample, actual string) (bool, string, error)
+}
+
+func IsBuiltinJudge(name string) bool {
+	for _, n := range JudgeNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func NewJudge(name string, tolerance Tolerance) (Judge, error) {
+	switch name {
+	case JudgeExact:
+		return &ExactJudge{}, nil
+	case JudgeWhitespace:
+		return &WhitespaceJudge{}, nil
+	case JudgeUnordered:
+		return &UnorderedJudge{}, nil
+	case JudgeFloat:
+		return &FloatJudge{tolerance: tolerance}, nil
+	case JudgeNoCase:
+		return &NoCaseJudge{}, nil
+	default:
+		return nil, fmt.Errorf("unknown judge '%s'. available judges: %s", name, strings.Join(JudgeNames, ", "))
+	}
+}
+
+type ExactJudge struct{}
+
+func (j *ExactJudge) Name() string {
+	return JudgeExact
+}
+
+func (j *ExactJudge) Judge(sample Sample, actual string) (bool, string, error) {
+	return sample.Output == actual, "", nil
+}
+
+// WhitespaceJudge ignores the amount and kind of whitespaces between tokens.
+type WhitespaceJudge struct{}
+
+func (j *WhitespaceJudge) Name() string {
+	return JudgeWhitespace
+}
+
+func (j *WhitespaceJudge) Judge(sample Sample, actual string) (bool, string, error) {
+	return equalStrings(strings.Fields(sample.Output), strings.Fields(actual)), "", nil
+}
+
+// UnorderedJudge ignores the order of lines.
+type UnorderedJudge struct{}
+
+func (j *UnorderedJudge) Name() string {
+	return JudgeUnordered
+}
+
+func (j *UnorderedJudge) Judge(sample Sample, actual string) (bool, string, error) {
+	expectedLines := normalizedLines(sample.Output)
+	actualLines := normalizedLines(actual)
+	sort.Strings(expectedLines)
+	sort.Strings(actualLines)
+
+	return equalStrings(expectedLines, actualLines), "", nil
+}
+
+type FloatJudge struct {
+	tolerance Tolerance
+}
+
+func (j *FloatJudge) Name() string {
+	return JudgeFloat
+}
+
+func (j *FloatJudge) Judge(sample Sample, actual string) (bool, string, error) {
+	return j.tolerance.match(sample.Output, actual), "", nil
+}
+
+// NoCaseJudge ignores the case of letters. e.g.) "Yes" and "YES"
+type NoCaseJudge struct{}
+
+func (j *NoCaseJudge) Name() string {
+	return JudgeNoCase
+}
+
+func (j *NoCaseJudge) Judge(sample Sample, actual string) (bool, string, error) {
+	return strings.EqualFold(sample.Output, actual), "", nil
+}
+
+func normalizedLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
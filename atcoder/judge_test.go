@@ -0,0 +1,194 @@
+package atcoder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewJudge(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputName      string
+		expectedName   string
+		expectedErrMsg string
+	}{
+		{
+			name:         "success-exact",
+			inputName:    "exact",
+			expectedName: "exact",
+		},
+		{
+			name:         "success-float",
+			inputName:    "float",
+			expectedName: "float",
+		},
+		{
+			name:           "failure-unknown",
+			inputName:      "fuzzy",
+			expectedErrMsg: "unknown judge 'fuzzy'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			judge, err := NewJudge(test.inputName, Tolerance{Absolute: 1e-6, Relative: 1e-6})
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if judge.Name() != test.expectedName {
+					t.Fatalf("name wrong. want=%s, got=%s", test.expectedName, judge.Name())
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestJudge_Judge(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputJudge    Judge
+		inputExpected string
+		inputActual   string
+		expected      bool
+	}{
+		{
+			name:          "exact-same",
+			inputJudge:    &ExactJudge{},
+			inputExpected: "1 2\n",
+			inputActual:   "1 2\n",
+			expected:      true,
+		},
+		{
+			name:          "exact-trailing space",
+			inputJudge:    &ExactJudge{},
+			inputExpected: "1 2\n",
+			inputActual:   "1 2 \n",
+			expected:      false,
+		},
+		{
+			name:          "whitespace-different spacing",
+			inputJudge:    &WhitespaceJudge{},
+			inputExpected: "1 2\n3\n",
+			inputActual:   "1  2 3",
+			expected:      true,
+		},
+		{
+			name:          "whitespace-different token",
+			inputJudge:    &WhitespaceJudge{},
+			inputExpected: "1 2\n",
+			inputActual:   "1 3\n",
+			expected:      false,
+		},
+		{
+			name:          "unordered-swapped lines",
+			inputJudge:    &UnorderedJudge{},
+			inputExpected: "1 2\n3 4\n",
+			inputActual:   "3 4\n1 2 \n\n",
+			expected:      true,
+		},
+		{
+			name:          "unordered-duplicated line",
+			inputJudge:    &UnorderedJudge{},
+			inputExpected: "1 2\n3 4\n",
+			inputActual:   "1 2\n1 2\n",
+			expected:      false,
+		},
+		{
+			name:          "float-within error",
+			inputJudge:    &FloatJudge{tolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6}},
+			inputExpected: "0.5\n",
+			inputActual:   "0.5000001\n",
+			expected:      true,
+		},
+		{
+			name:          "nocase-different case",
+			inputJudge:    &NoCaseJudge{},
+			inputExpected: "Yes\n",
+			inputActual:   "YES\n",
+			expected:      true,
+		},
+		{
+			name:          "nocase-different word",
+			inputJudge:    &NoCaseJudge{},
+			inputExpected: "Yes\n",
+			inputActual:   "No\n",
+			expected:      false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, _, err := test.inputJudge.Judge(Sample{Output: test.inputExpected}, test.inputActual)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if actual != test.expected {
+				t.Fatalf("judge wrong. want=%t, got=%t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSpecialJudge_Judge(t *testing.T) {
+	tests := []struct {
+		name            string
+		mockResults     []commandResult
+		expectedSuccess bool
+		expectedMessage string
+		expectedErrMsg  string
+	}{
+		{
+			name: "accepted",
+			mockResults: []commandResult{
+				{output: "", err: nil},
+			},
+			expectedSuccess: true,
+		},
+		{
+			name: "rejected",
+			mockResults: []commandResult{
+				{output: "", exitCode: 1, stderr: "wrong answer: not a permutation\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedMessage: "wrong answer: not a permutation",
+		},
+		{
+			name: "failed to execute",
+			mockResults: []commandResult{
+				{output: "", err: errors.New("fork/exec /bin/bash: no such file or directory")},
+			},
+			expectedErrMsg: "failed to execute special judge",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j := &SpecialJudge{command: dummyRawCommand, commander: &testCommander{index: 0, results: test.mockResults}}
+			success, message, err := j.Judge(Sample{Input: "3\n", Output: "1 2 3\n"}, "3 2 1\n")
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if success != test.expectedSuccess {
+				t.Fatalf("success wrong. want=%t, got=%t", test.expectedSuccess, success)
+			}
+			if !strings.Contains(message, test.expectedMessage) {
+				t.Fatalf("expect '%s' to contain '%s'", message, test.expectedMessage)
+			}
+		})
+	}
+}
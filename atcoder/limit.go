@@ -0,0 +1,47 @@
+package atcoder
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// e.g.) "実行時間制限: 2 sec / メモリ制限: 1024 MiB", "Time Limit: 2 sec / Memory Limit: 1024 MiB". older pages use "MB"
+var (
+	timeLimitRegexp   = regexp.MustCompile(`(?:実行時間制限|Time Limit)\s*:\s*([\d.]+)\s*sec`)
+	memoryLimitRegexp = regexp.MustCompile(`(?:メモリ制限|Memory Limit)\s*:\s*(\d+)\s*([KMG]i?B)`)
+)
+
+func parseTimeLimit(text string) time.Duration {
+	matches := timeLimitRegexp.FindStringSubmatch(text)
+	if matches == nil {
+		return 0
+	}
+
+	sec, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(sec * float64(time.Second))
+}
+
+// parseMemoryLimit returns the memory limit in bytes. "MB" on older pages of AtCoder also means mebibyte.
+func parseMemoryLimit(text string) int64 {
+	matches := memoryLimitRegexp.FindStringSubmatch(text)
+	if matches == nil {
+		return 0
+	}
+
+	size, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	switch matches[2] {
+	case "KB", "KiB":
+		return size << 10
+	case "MB", "MiB":
+		return size << 20
+	default:
+		return size << 30
+	}
+}
@@ -0,0 +1,100 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_parseTimeLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputText string
+		expected  time.Duration
+	}{
+		{
+			name:      "success-japanese",
+			inputText: "実行時間制限: 2 sec / メモリ制限: 1024 MB",
+			expected:  2 * time.Second,
+		},
+		{
+			name:      "success-english",
+			inputText: "Time Limit: 5.25 sec / Memory Limit: 256 MB",
+			expected:  5250 * time.Millisecond,
+		},
+		{
+			name:      "success-not found",
+			inputText: "配点 : 200 点",
+			expected:  0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := parseTimeLimit(test.inputText)
+			if actual != test.expected {
+				t.Fatalf("time limit wrong. want=%s, got=%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_parseMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputText string
+		expected  int64
+	}{
+		{
+			name:      "success-japanese",
+			inputText: "実行時間制限: 2 sec / メモリ制限: 1024 MB",
+			expected:  1024 * 1024 * 1024,
+		},
+		{
+			name:      "success-english",
+			inputText: "Time Limit: 2 sec / Memory Limit: 64 MB",
+			expected:  64 * 1024 * 1024,
+		},
+		{
+			name:      "success-japanese mebibyte",
+			inputText: "実行時間制限: 2 sec / メモリ制限: 1024 MiB",
+			expected:  1024 * 1024 * 1024,
+		},
+		{
+			name:      "success-english mebibyte",
+			inputText: "Time Limit: 2 sec / Memory Limit: 1024 MiB",
+			expected:  1024 * 1024 * 1024,
+		},
+		{
+			name:      "success-gibibyte",
+			inputText: "Time Limit: 2 sec / Memory Limit: 2 GiB",
+			expected:  2 * 1024 * 1024 * 1024,
+		},
+		{
+			name:      "success-not found",
+			inputText: "配点 : 200 点",
+			expected:  0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := parseMemoryLimit(test.inputText)
+			if actual != test.expected {
+				t.Fatalf("memory limit wrong. want=%d, got=%d", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_parseMemoryLimit_page(t *testing.T) {
+	// the problem page in the current format, which prints the memory limit in MiB
+	page, err := ioutil.ReadFile(filepath.Join("testdata", "problem", "abc124b_mib.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := int64(1024 * 1024 * 1024)
+	if actual := parseMemoryLimit(string(page)); actual != expected {
+		t.Fatalf("memory limit wrong. want=%d, got=%d", expected, actual)
+	}
+}
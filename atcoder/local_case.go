@@ -0,0 +1,104 @@
+package atcoder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// local test cases are pairs of {name}.in and {name}.out like online-judge-tools
+const (
+	localCaseInputExt   = ".in"
+	localCaseOutputExt  = ".out"
+	localCaseNamePrefix = "case_"
+)
+
+var trailingNumberRegexp = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// ReadLocalCases reads the test cases added by the user from dir in order of their names.
+// nil is returned if dir does not exist.
+func ReadLocalCases(dir string) ([]Sample, error) {
+	inputPaths, err := filepath.Glob(filepath.Join(dir, "*"+localCaseInputExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find local test cases: %s", err)
+	}
+
+	names := make([]string, len(inputPaths))
+	for i, p := range inputPaths {
+		names[i] = strings.TrimSuffix(filepath.Base(p), localCaseInputExt)
+	}
+	sort.Slice(names, func(i, j int) bool { return naturalLess(names[i], names[j]) })
+
+	var cases []Sample
+	for _, name := range names {
+		input, err := ioutil.ReadFile(filepath.Join(dir, name+localCaseInputExt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read local test case '%s': %s", name, err)
+		}
+		output, err := ioutil.ReadFile(filepath.Join(dir, name+localCaseOutputExt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read expected output of local test case '%s': %s", name, err)
+		}
+		cases = append(cases, Sample{Input: string(input), Output: string(output), Name: name})
+	}
+	return cases, nil
+}
+
+// AddLocalCase writes the test case into dir and returns its name.
+// the name is numbered like case_1 if the name of the sample is empty.
+// an existing test case of the same name is overwritten only if overwrite is true.
+func AddLocalCase(dir string, sample Sample, overwrite bool) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for local test cases: %s", err)
+	}
+
+	name := sample.Name
+	if name == "" {
+		for i := 1; ; i++ {
+			name = fmt.Sprintf("%s%d", localCaseNamePrefix, i)
+			if _, err := os.Stat(filepath.Join(dir, name+localCaseInputExt)); os.IsNotExist(err) {
+				break
+			}
+		}
+	} else if strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("name of local test case should not contain path separators: %s", name)
+	} else if !overwrite && LocalCaseExists(dir, name) {
+		return "", fmt.Errorf("test case '%s' already exists in %s. use -force to overwrite it", name, dir)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, name+localCaseInputExt), []byte(sample.Input), 0644); err != nil {
+		return "", fmt.Errorf("failed to write local test case: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, name+localCaseOutputExt), []byte(sample.Output), 0644); err != nil {
+		return "", fmt.Errorf("failed to write local test case: %s", err)
+	}
+	return name, nil
+}
+
+// LocalCaseExists returns true if the input or the expected output of the test case exists in dir.
+func LocalCaseExists(dir, name string) bool {
+	for _, ext := range []string{localCaseInputExt, localCaseOutputExt} {
+		if _, err := os.Stat(filepath.Join(dir, name+ext)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// naturalLess compares names by their trailing numbers so that case_2 comes before case_10.
+func naturalLess(a, b string) bool {
+	ma, mb := trailingNumberRegexp.FindStringSubmatch(a), trailingNumberRegexp.FindStringSubmatch(b)
+	if ma != nil && mb != nil && ma[1] == mb[1] {
+		na, _ := strconv.Atoi(ma[2])
+		nb, _ := strconv.Atoi(mb[2])
+		if na != nb {
+			return na < nb
+		}
+	}
+	return a < b
+}
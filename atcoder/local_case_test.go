@@ -0,0 +1,69 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocalCases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atctest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testsDir := filepath.Join(dir, "tests")
+
+	cases, err := ReadLocalCases(testsDir)
+	if err != nil || cases != nil {
+		t.Fatalf("no cases should be read from nonexistent directory. got: %v, %v", cases, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := AddLocalCase(testsDir, Sample{Input: "1\n", Output: "1\n"}, false); err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+	}
+	name, err := AddLocalCase(testsDir, Sample{Input: "200000\n", Output: "0\n", Name: "max"}, false)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if name != "max" {
+		t.Fatalf("name wrong. want=max, got=%s", name)
+	}
+	if _, err := AddLocalCase(testsDir, Sample{Input: "1\n", Output: "1\n", Name: "max"}, false); err == nil || !strings.Contains(err.Error(), "test case 'max' already exists") {
+		t.Fatalf("existing case should not be overwritten. got: %v", err)
+	}
+	if _, err := AddLocalCase(testsDir, Sample{Input: "200000\n", Output: "0\n", Name: "max"}, true); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if _, err := AddLocalCase(testsDir, Sample{Name: "../escape"}, false); err == nil || !strings.Contains(err.Error(), "path separators") {
+		t.Fatalf("name with path separators should be rejected. got: %v", err)
+	}
+
+	cases, err = ReadLocalCases(testsDir)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	var names []string
+	for _, c := range cases {
+		names = append(names, c.Name)
+	}
+	expectedNames := []string{"case_1", "case_2", "case_3", "case_4", "case_5", "case_6", "case_7", "case_8", "case_9", "case_10", "max"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("names wrong. want=%v, got=%v", expectedNames, names)
+	}
+	if last := cases[len(cases)-1]; last.Input != "200000\n" || last.Output != "0\n" {
+		t.Fatalf("case wrong. got=%+v", last)
+	}
+
+	if err := os.Remove(filepath.Join(testsDir, "max.out")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLocalCases(testsDir); err == nil || !strings.Contains(err.Error(), "expected output of local test case 'max'") {
+		t.Fatalf("case without output should be an error. got: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package atcoder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// layouts of sample files
+const (
+	SampleLayoutTxt = "txt" // in_1.txt, out_1.txt, ...
+	SampleLayoutOJ  = "oj"  // sample-1.in, sample-1.out, ... like online-judge-tools
+)
+
+var SampleLayouts = []string{SampleLayoutTxt, SampleLayoutOJ}
+
+func IsSampleLayout(layout string) bool {
+	for _, l := range SampleLayouts {
+		if l == layout {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultSampleDir returns the directory name of samples for the layout. online-judge-tools uses test/.
+func DefaultSampleDir(layout string) string {
+	if layout == SampleLayoutOJ {
+		return "test"
+	}
+	return "samples"
+}
+
+// sampleFileNames returns the names of the input and output files of the index-th sample (1-indexed).
+func sampleFileNames(layout string, index int) (string, string) {
+	if layout == SampleLayoutOJ {
+		return fmt.Sprintf("sample-%d.in", index), fmt.Sprintf("sample-%d.out", index)
+	}
+	return fmt.Sprintf("in_%d.txt", index), fmt.Sprintf("out_%d.txt", index)
+}
+
+// WriteSamples writes the samples into dir in the layout and returns the paths of the files.
+func WriteSamples(dir, layout string, samples []Sample) ([]string, error) {
+	if !IsSampleLayout(layout) {
+		return nil, fmt.Errorf("unknown layout of samples '%s'", layout)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for samples: %s", err)
+	}
+
+	var paths []string
+	for i, sample := range samples {
+		inputName, outputName := sampleFileNames(layout, i+1)
+		files := []struct {
+			name    string
+			content string
+		}{
+			{name: inputName, content: sample.Input},
+			{name: outputName, content: sample.Output},
+		}
+		for _, f := range files {
+			p := filepath.Join(dir, f.name)
+			if err := ioutil.WriteFile(p, []byte(f.content), 0644); err != nil {
+				return nil, fmt.Errorf("failed to write sample: %s", err)
+			}
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,83 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteSamples(t *testing.T) {
+	samples := []Sample{
+		{Input: "1 2\n", Output: "3\n"},
+		{Input: "10 20\n", Output: "30\n"},
+	}
+
+	tests := []struct {
+		name           string
+		inputLayout    string
+		expectedFiles  []string
+		expectedErrMsg string
+	}{
+		{
+			name:          "success-txt",
+			inputLayout:   SampleLayoutTxt,
+			expectedFiles: []string{"in_1.txt", "out_1.txt", "in_2.txt", "out_2.txt"},
+		},
+		{
+			name:          "success-oj",
+			inputLayout:   SampleLayoutOJ,
+			expectedFiles: []string{"sample-1.in", "sample-1.out", "sample-2.in", "sample-2.out"},
+		},
+		{
+			name:           "failure-unknown layout",
+			inputLayout:    "zip",
+			expectedErrMsg: "unknown layout of samples 'zip'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "atctest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			paths, err := WriteSamples(filepath.Join(dir, "samples"), test.inputLayout, samples)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			var expectedPaths []string
+			for _, name := range test.expectedFiles {
+				expectedPaths = append(expectedPaths, filepath.Join(dir, "samples", name))
+			}
+			if !reflect.DeepEqual(paths, expectedPaths) {
+				t.Errorf("paths wrong. want=%v, got=%v", expectedPaths, paths)
+			}
+
+			expectedContents := []string{"1 2\n", "3\n", "10 20\n", "30\n"}
+			for i, p := range expectedPaths {
+				bytes, err := ioutil.ReadFile(p)
+				if err != nil {
+					t.Fatalf("failed to read %s: %s", p, err)
+				}
+				if string(bytes) != expectedContents[i] {
+					t.Errorf("content of %s wrong. want=%q, got=%q", p, expectedContents[i], string(bytes))
+				}
+			}
+		})
+	}
+}
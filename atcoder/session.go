@@ -0,0 +1,98 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sessionCookieName = "REVEL_SESSION"
+	sessionFileName   = "session"
+)
+
+// the session cookie contains its expiration time as unix time. e.g.) "...%00_TS%3A1590000000%00..."
+var sessionExpirationRegexp = regexp.MustCompile(`_TS%3A(\d+)`)
+
+// SessionFilePath returns the path of the file in which the login session is stored.
+func SessionFilePath(cacheDirPath string) string {
+	return path.Join(cacheDirPath, sessionFileName)
+}
+
+// loadSession sets the stored session cookie to the collector. it does nothing if the session is not stored.
+func (c *Client) loadSession() error {
+	if c.cacheDirPath == "" {
+		return nil
+	}
+
+	bytes, err := ioutil.ReadFile(SessionFilePath(c.cacheDirPath))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read session file: %s", err)
+	}
+
+	cookie := &http.Cookie{Name: sessionCookieName, Value: strings.TrimSpace(string(bytes))}
+	if err := c.collector.SetCookies(c.baseURL, []*http.Cookie{cookie}); err != nil {
+		return fmt.Errorf("failed to set session: %s", err)
+	}
+	c.sessionLoaded = true
+	return nil
+}
+
+// SaveSession stores the session cookie of the logged-in client so that following runs do not need to log in.
+// the file is readable only by the user because the session works as the password.
+func (c *Client) SaveSession() error {
+	var value string
+	for _, cookie := range c.collector.Cookies(c.baseURL) {
+		if cookie.Name == sessionCookieName {
+			value = cookie.Value
+		}
+	}
+	if value == "" {
+		return fmt.Errorf("could not find %s cookie. you may not be logged in", sessionCookieName)
+	}
+	if c.cacheDirPath == "" {
+		return errors.New("could not find the directory to store the session")
+	}
+
+	if err := os.MkdirAll(c.cacheDirPath, 0700); err != nil {
+		return fmt.Errorf("failed to create directory for session: %s", err)
+	}
+	sessionFilePath := SessionFilePath(c.cacheDirPath)
+	if err := ioutil.WriteFile(sessionFilePath, []byte(value), 0600); err != nil {
+		return fmt.Errorf("failed to save session: %s", err)
+	}
+	// WriteFile does not change the permission of an existing file
+	return os.Chmod(sessionFilePath, 0600)
+}
+
+// ClearSession removes the stored session. it is not an error if the session is not stored.
+func (c *Client) ClearSession() error {
+	if c.cacheDirPath == "" {
+		return nil
+	}
+	if err := os.Remove(SessionFilePath(c.cacheDirPath)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove session: %s", err)
+	}
+	return nil
+}
+
+func sessionExpired(value string, now time.Time) bool {
+	m := sessionExpirationRegexp.FindStringSubmatch(value)
+	if m == nil {
+		return false
+	}
+	expiration, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return false
+	}
+	return now.Unix() >= expiration
+}
@@ -0,0 +1,109 @@
+package atcoder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+func dummySessionValue(username string, expiration time.Time) string {
+	return fmt.Sprintf("0123456789abcdef-%%00_TS%%3A%d%%00%%00UserScreenName%%3A%s%%00", expiration.Unix(), username)
+}
+
+func TestClient_SaveSession(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputExpiration time.Time
+
+		expectedLoggedIn bool
+		expectedErrMsg   string
+	}{
+		{
+			name:             "success-valid session",
+			inputExpiration:  time.Now().Add(time.Hour),
+			expectedLoggedIn: true,
+		},
+		{
+			name:             "failure-expired session",
+			inputExpiration:  time.Now().Add(-time.Hour),
+			expectedLoggedIn: false,
+			expectedErrMsg:   "the stored session has expired",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cacheDirPath, err := ioutil.TempDir("", "atctest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(cacheDirPath)
+
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), cacheDirPath: cacheDirPath}
+			cookie := &http.Cookie{Name: sessionCookieName, Value: dummySessionValue("mui87", test.inputExpiration)}
+			if err := c.collector.SetCookies(dummyBaseURL, []*http.Cookie{cookie}); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.SaveSession(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			info, err := os.Stat(SessionFilePath(cacheDirPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Fatalf("permission wrong. want=%o, got=%o", 0600, info.Mode().Perm())
+			}
+
+			var errStream strings.Builder
+			loaded := NewClient(dummyBaseURL, false, cacheDirPath, ioutil.Discard, &errStream)
+			if errStream.Len() > 0 {
+				t.Fatalf("session should be loaded without error. got: %s", errStream.String())
+			}
+			if loaded.IsLoggedIn("mui87") != test.expectedLoggedIn {
+				t.Fatalf("logged in wrong. want=%t, got=%t", test.expectedLoggedIn, !test.expectedLoggedIn)
+			}
+
+			err = loaded.LogIn("", "")
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+
+			if err := loaded.ClearSession(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if _, err := os.Stat(SessionFilePath(cacheDirPath)); !os.IsNotExist(err) {
+				t.Fatal("session file should be removed")
+			}
+		})
+	}
+}
+
+func TestClient_SaveSession_notLoggedIn(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), cacheDirPath: cacheDirPath}
+	if err := c.SaveSession(); err == nil || !strings.Contains(err.Error(), "you may not be logged in") {
+		t.Fatalf("err should tell that client is not logged in. got: %v", err)
+	}
+}
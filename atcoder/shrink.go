@@ -0,0 +1,368 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shrinker proposes smaller variants of a failing input. the variants are tried in order,
+// and the first one on which the program still fails replaces the input.
+// shrinkers which know the structure of inputs can keep them valid, e.g. by decreasing N with the array.
+type Shrinker interface {
+	Name() string
+	Candidates(input string) []string
+}
+
+// names of builtin shrinkers
+const (
+	ShrinkSpec    = "spec"
+	ShrinkLines   = "lines"
+	ShrinkTokens  = "tokens"
+	ShrinkNumbers = "numbers"
+)
+
+var ShrinkerNames = []string{ShrinkSpec, ShrinkLines, ShrinkTokens, ShrinkNumbers}
+
+// DefaultShrinkerNames are the shrinkers which do not need the spec of inputs.
+var DefaultShrinkerNames = []string{ShrinkLines, ShrinkTokens, ShrinkNumbers}
+
+// NewShrinker returns the builtin shrinker for the name. spec is required only by the spec shrinker.
+func NewShrinker(name string, spec *Spec) (Shrinker, error) {
+	switch name {
+	case ShrinkSpec:
+		if spec == nil {
+			return nil, errors.New("shrinker 'spec' needs the spec of inputs. e.g.) -spec 'N int [1,10]; A [N]int [1,100]'")
+		}
+		return &SpecShrinker{Spec: spec}, nil
+	case ShrinkLines:
+		return &LineShrinker{}, nil
+	case ShrinkTokens:
+		return &TokenShrinker{}, nil
+	case ShrinkNumbers:
+		return &NumberShrinker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown shrinker '%s'. available shrinkers: %s", name, strings.Join(ShrinkerNames, ", "))
+	}
+}
+
+// LineShrinker removes chunks of lines like delta debugging. larger chunks are tried first.
+type LineShrinker struct{}
+
+func (s *LineShrinker) Name() string { return ShrinkLines }
+
+func (s *LineShrinker) Candidates(input string) []string {
+	lines := strings.SplitAfter(input, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var candidates []string
+	for _, removed := range removeChunks(len(lines)) {
+		var b strings.Builder
+		for i, line := range lines {
+			if !removed(i) {
+				b.WriteString(line)
+			}
+		}
+		candidates = append(candidates, b.String())
+	}
+	return candidates
+}
+
+// TokenShrinker removes chunks of whitespace-separated tokens in lines, which shrinks long lines. lines are never emptied.
+type TokenShrinker struct{}
+
+func (s *TokenShrinker) Name() string { return ShrinkTokens }
+
+func (s *TokenShrinker) Candidates(input string) []string {
+	lines := splitTokenLines(input)
+	total := 0
+	for _, tokens := range lines {
+		total += len(tokens)
+	}
+
+	var candidates []string
+	for _, removed := range removeChunks(total) {
+		if emptiesLine(lines, removed) {
+			continue
+		}
+		candidates = append(candidates, joinTokenLines(lines, func(i int, token string) (string, bool) {
+			return token, !removed(i)
+		}))
+	}
+	return candidates
+}
+
+// emptiesLine reports whether all tokens of a line are removed, which is left to LineShrinker.
+func emptiesLine(lines [][]string, removed func(i int) bool) bool {
+	index := 0
+	for _, tokens := range lines {
+		kept := 0
+		for range tokens {
+			if !removed(index) {
+				kept++
+			}
+			index++
+		}
+		if len(tokens) > 0 && kept == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NumberShrinker makes integers closer to zero one by one, trying 0, 1, the half of the value and the next one.
+type NumberShrinker struct{}
+
+func (s *NumberShrinker) Name() string { return ShrinkNumbers }
+
+func (s *NumberShrinker) Candidates(input string) []string {
+	lines := splitTokenLines(input)
+	index := 0
+	var candidates []string
+	for _, tokens := range lines {
+		for _, token := range tokens {
+			target := index
+			index++
+
+			n, err := strconv.ParseInt(token, 10, 64)
+			if err != nil {
+				continue
+			}
+			closer := n - 1
+			if n < 0 {
+				closer = n + 1
+			}
+			seen := map[int64]bool{n: true}
+			for _, smaller := range []int64{0, 1, n / 2, closer} {
+				if seen[smaller] || abs(smaller) > abs(n) {
+					continue
+				}
+				seen[smaller] = true
+				value := strconv.FormatInt(smaller, 10)
+				candidates = append(candidates, joinTokenLines(lines, func(i int, token string) (string, bool) {
+					if i == target {
+						return value, true
+					}
+					return token, true
+				}))
+			}
+		}
+	}
+	return candidates
+}
+
+// SpecShrinker makes integers of the spec smaller together with the arrays, strings and graphs whose sizes they are.
+// e.g.) elements of A are removed with decreasing N for 'N int [1,10]; A [N]int [1,100]', and vertices are removed from trees.
+// candidates which do not follow the spec are not proposed.
+type SpecShrinker struct {
+	Spec *Spec
+}
+
+func (s *SpecShrinker) Name() string { return ShrinkSpec }
+
+func (s *SpecShrinker) Candidates(input string) []string {
+	parts, vars, err := s.Spec.split(input)
+	if err != nil {
+		return nil
+	}
+	names, sizes := s.Spec.integers()
+
+	var candidates []string
+	seen := map[string]bool{input: true}
+	add := func(name string, value int64, removed func(i int64) bool) {
+		candidate, err := s.Spec.shrink(parts, vars, name, value, removed)
+		if err != nil || seen[candidate] || s.Spec.Validate(candidate) != nil {
+			return
+		}
+		seen[candidate] = true
+		candidates = append(candidates, candidate)
+	}
+
+	for _, name := range names {
+		n := vars[name]
+		// the same values as NumberShrinker, which truncate arrays of the size
+		closer := n - 1
+		if n < 0 {
+			closer = n + 1
+		}
+		for _, smaller := range []int64{0, 1, n / 2, closer} {
+			if smaller == n || abs(smaller) > abs(n) {
+				continue
+			}
+			add(name, smaller, func(i int64) bool { return i >= smaller })
+		}
+		// elements in the middle are removed like LineShrinker
+		if sizes[name] && n > 0 {
+			for _, removed := range removeChunks(int(n)) {
+				count := int64(0)
+				for i := 0; i < int(n); i++ {
+					if removed(i) {
+						count++
+					}
+				}
+				add(name, n-count, func(i int64) bool { return removed(int(i)) })
+			}
+		}
+	}
+	return candidates
+}
+
+// removeChunks returns predicates telling which units are removed for each candidate.
+// the units are split into 2, 4, 8, ... chunks and each chunk is removed in turn.
+func removeChunks(n int) []func(i int) bool {
+	var removes []func(i int) bool
+	if n == 0 {
+		return removes
+	}
+	for chunks := 2; ; chunks *= 2 {
+		if chunks > n {
+			chunks = n
+		}
+		size := (n + chunks - 1) / chunks
+		for start := 0; start < n; start += size {
+			start, end := start, start+size
+			if start == 0 && end >= n {
+				continue
+			}
+			removes = append(removes, func(i int) bool { return start <= i && i < end })
+		}
+		if chunks == n {
+			return removes
+		}
+	}
+}
+
+func splitTokenLines(input string) [][]string {
+	lines := strings.Split(strings.TrimSuffix(input, "\n"), "\n")
+	tokens := make([][]string, len(lines))
+	for i, line := range lines {
+		tokens[i] = strings.Fields(line)
+	}
+	return tokens
+}
+
+// joinTokenLines joins tokens into lines. f returns the token at the index over all lines and whether it is kept.
+func joinTokenLines(lines [][]string, f func(i int, token string) (string, bool)) string {
+	var b strings.Builder
+	index := 0
+	for _, tokens := range lines {
+		var kept []string
+		for _, token := range tokens {
+			if t, ok := f(index, token); ok {
+				kept = append(kept, t)
+			}
+			index++
+		}
+		b.WriteString(strings.Join(kept, " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ShrinkOption is the setting of shrinking failing inputs.
+type ShrinkOption struct {
+	// command of the naive solution whose output is expected
+	Reference string
+	Shrinkers []Shrinker
+	// command which reads a candidate and exits with non-zero code if it is not a valid input. candidates are not checked if empty
+	Validator string
+	// candidates which do not follow the spec are skipped if it is given
+	Spec *Spec
+	// max number of runs of the program. shrinking stops with the smallest input so far when it is exceeded
+	MaxRuns int
+
+	TimeLimit   time.Duration
+	MemoryLimit int64
+}
+
+// Shrink makes the failing sample smaller while the program fails with the same verdict as result.
+// the expected output of each candidate is given by the reference, and candidates on which the reference fails are skipped.
+// candidates which do not follow the spec or are rejected by the validator are skipped without running the program.
+// inputs only get smaller in length (or in lexicographic order for the same length), so shrinking always terminates.
+func (c *Checker) Shrink(command string, sample Sample, result *SampleResult, judge Judge, option ShrinkOption) (Sample, *SampleResult) {
+	runs := 0
+	// the program is assumed to be deterministic, so candidates are not tried twice
+	tried := map[string]bool{}
+	for progress := true; progress; {
+		progress = false
+		for _, shrinker := range option.Shrinkers {
+			for _, candidate := range shrinker.Candidates(sample.Input) {
+				if tried[candidate] || len(candidate) > len(sample.Input) || (len(candidate) == len(sample.Input) && candidate >= sample.Input) {
+					continue
+				}
+				tried[candidate] = true
+				if option.Spec != nil && option.Spec.Validate(candidate) != nil {
+					continue
+				}
+				if option.Validator != "" {
+					if _, err := c.runHelper("validator", option.Validator, candidate); err != nil {
+						continue
+					}
+				}
+				if option.MaxRuns > 0 && runs >= option.MaxRuns {
+					_, _ = fmt.Fprintln(c.outStream)
+					return sample, result
+				}
+				runs++
+
+				expected, err := c.runHelper("reference", option.Reference, candidate)
+				if err != nil {
+					continue
+				}
+				s := Sample{Input: candidate, Output: expected, Name: sample.Name}
+				if r := c.checkOne(command, s, judge, option.TimeLimit, option.MemoryLimit); r.Verdict == result.Verdict {
+					sample, result = s, r
+					progress = true
+					_, _ = fmt.Fprintf(c.outStream, "\rshrink: %d bytes (%s)", len(sample.Input), shrinker.Name())
+					break
+				}
+			}
+			if progress {
+				break
+			}
+		}
+	}
+	_, _ = fmt.Fprintln(c.outStream)
+	return sample, result
+}
+
+// ShrinkInput checks the program on the input against the reference, and shrinks the input if the program fails.
+// the smallest input is reported by the formatter and returned with its result. nil is returned if the program does not fail.
+func (c *Checker) ShrinkInput(command string, sample Sample, judge Judge, option ShrinkOption) (*Sample, *SampleResult, error) {
+	if option.Spec != nil {
+		if err := option.Spec.Validate(sample.Input); err != nil {
+			return nil, nil, fmt.Errorf("input does not follow the spec: %s", err)
+		}
+	}
+	if option.Validator != "" {
+		if _, err := c.runHelper("validator", option.Validator, sample.Input); err != nil {
+			return nil, nil, fmt.Errorf("input is rejected by the validator: %s", err)
+		}
+	}
+	expected, err := c.runHelper("reference", option.Reference, sample.Input)
+	if err != nil {
+		return nil, nil, err
+	}
+	sample.Output = expected
+
+	result := c.checkOne(command, sample, judge, option.TimeLimit, option.MemoryLimit)
+	if result.Verdict == VerdictSuccess {
+		_, _ = fmt.Fprintln(c.outStream, "your program does not fail on the input")
+		return nil, nil, nil
+	}
+
+	sample, result = c.Shrink(command, sample, result, judge, option)
+	c.reportOne(1, sample, result, judge, option.TimeLimit, option.MemoryLimit)
+	return &sample, result, nil
+}
@@ -0,0 +1,250 @@
+package atcoder
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mui87/atctest/commander"
+)
+
+func mustParseSpec(t *testing.T, text string) *Spec {
+	spec, err := ParseSpec(text)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	return spec
+}
+
+func TestShrinker_Candidates(t *testing.T) {
+	tests := []struct {
+		name               string
+		inputShrinker      Shrinker
+		inputInput         string
+		expectedCandidates []string
+	}{
+		{
+			name:          "lines",
+			inputShrinker: &LineShrinker{},
+			inputInput:    "a\nb\nc\n",
+			expectedCandidates: []string{
+				"c\n", "a\nb\n",
+				"b\nc\n", "a\nc\n", "a\nb\n",
+			},
+		},
+		{
+			name:          "tokens",
+			inputShrinker: &TokenShrinker{},
+			inputInput:    "ab\n10 20 30\n",
+			expectedCandidates: []string{
+				"ab\n10\n",
+				"ab\n20 30\n", "ab\n10 30\n", "ab\n10 20\n",
+			},
+		},
+		{
+			name:          "spec-array with its size",
+			inputShrinker: &SpecShrinker{Spec: mustParseSpec(t, "N int [1,10]; A [N]int [1,100]")},
+			inputInput:    "3\n10 20 30\n",
+			expectedCandidates: []string{
+				"1\n10\n", "2\n10 20\n",
+				"1\n30\n", "2\n20 30\n", "2\n10 30\n",
+			},
+		},
+		{
+			name:          "spec-grid",
+			inputShrinker: &SpecShrinker{Spec: mustParseSpec(t, "H W int [1,3]; G [H][W]char [.#]")},
+			inputInput:    "2 2\n.#\n#.\n",
+			expectedCandidates: []string{
+				"1 2\n.#\n", "1 2\n#.\n",
+				"2 1\n.\n#\n", "2 1\n#\n.\n",
+			},
+		},
+		{
+			name:          "spec-leaves of tree",
+			inputShrinker: &SpecShrinker{Spec: mustParseSpec(t, "N int [1,10]; edges tree N")},
+			inputInput:    "3\n1 2\n2 3\n",
+			expectedCandidates: []string{
+				"1\n", "2\n1 2\n",
+			},
+		},
+		{
+			name:          "spec-edges with removed vertices",
+			inputShrinker: &SpecShrinker{Spec: mustParseSpec(t, "N int [1,4]; M int [0,N]; edges graph N M; S [M]char [ab]")},
+			inputInput:    "3\n2\n1 2\n2 3\nab\n",
+			expectedCandidates: []string{
+				"1\n0\n\n", "2\n1\n1 2\na\n",
+				"2\n0\n\n",
+				"3\n0\n\n", "3\n1\n1 2\na\n", "3\n1\n2 3\nb\n",
+			},
+		},
+		{
+			name:          "spec-input not following the spec",
+			inputShrinker: &SpecShrinker{Spec: mustParseSpec(t, "N int [1,10]; A [N]int [1,100]")},
+			inputInput:    "2\n10\n",
+		},
+		{
+			name:          "numbers",
+			inputShrinker: &NumberShrinker{},
+			inputInput:    "2\n1 x -6\n",
+			expectedCandidates: []string{
+				"0\n1 x -6\n", "1\n1 x -6\n",
+				"2\n0 x -6\n",
+				"2\n1 x 0\n", "2\n1 x 1\n", "2\n1 x -3\n", "2\n1 x -5\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates := test.inputShrinker.Candidates(test.inputInput)
+			if !reflect.DeepEqual(candidates, test.expectedCandidates) {
+				t.Fatalf("candidates wrong.\nwant=%q\ngot= %q", test.expectedCandidates, candidates)
+			}
+		})
+	}
+}
+
+// sumCommander emulates the reference summing up tokens and the solution which is wrong if any token is 7 or more.
+// the reference fails on empty inputs, and the validator accepts only inputs of N and N integers.
+type sumCommander struct {
+	runs int
+}
+
+func (s *sumCommander) Run(command, stdin string, limit commander.Limit) (*commander.Result, error) {
+	s.runs++
+	tokens := strings.Fields(stdin)
+	if command == "validator" {
+		if lines := strings.Split(strings.TrimSuffix(stdin, "\n"), "\n"); len(lines) != 2 || lines[0] != strconv.Itoa(len(tokens)-1) {
+			return &commander.Result{ExitCode: 1}, nil
+		}
+		return &commander.Result{}, nil
+	}
+	sum, wrong := 0, false
+	for _, token := range tokens {
+		n, _ := strconv.Atoi(token)
+		sum += n
+		wrong = wrong || n >= 7
+	}
+	if command == "brute" {
+		if len(tokens) == 0 {
+			return &commander.Result{ExitCode: 1}, nil
+		}
+		return &commander.Result{Stdout: strconv.Itoa(sum) + "\n"}, nil
+	}
+	if wrong {
+		sum++
+	}
+	return &commander.Result{Stdout: strconv.Itoa(sum) + "\n"}, nil
+}
+
+func TestChecker_Shrink(t *testing.T) {
+	shrinkers := []Shrinker{&LineShrinker{}, &TokenShrinker{}, &NumberShrinker{}}
+	sample := Sample{Input: "5\n1 9 3 8 2\n", Output: "28\n", Name: "stress_1"}
+	result := &SampleResult{Verdict: VerdictFailure, Output: "29\n"}
+
+	tests := []struct {
+		name            string
+		inputValidator  string
+		inputShrinkers  []Shrinker
+		inputSpec       string
+		inputMaxRuns    int
+		expectedSample  Sample
+		expectedVerdict Verdict
+	}{
+		{
+			name:            "success-shrunk",
+			expectedSample:  Sample{Input: "7\n", Output: "7\n", Name: "stress_1"},
+			expectedVerdict: VerdictFailure,
+		},
+		{
+			name:            "success-shrunk with validator",
+			inputValidator:  "validator",
+			expectedSample:  Sample{Input: "5\n0 0 0 7 0\n", Output: "12\n", Name: "stress_1"},
+			expectedVerdict: VerdictFailure,
+		},
+		{
+			// only numbers are shrunk because removing lines or tokens breaks the structure
+			name:            "success-shrunk following the spec",
+			inputSpec:       "N int [1,5]; A [N]int [0,9]",
+			expectedSample:  Sample{Input: "5\n0 0 0 7 0\n", Output: "12\n", Name: "stress_1"},
+			expectedVerdict: VerdictFailure,
+		},
+		{
+			name:            "success-shrunk by the spec shrinker",
+			inputShrinkers:  append([]Shrinker{&SpecShrinker{Spec: mustParseSpec(t, "N int [1,5]; A [N]int [0,9]")}}, shrinkers...),
+			inputSpec:       "N int [1,5]; A [N]int [0,9]",
+			expectedSample:  Sample{Input: "1\n7\n", Output: "8\n", Name: "stress_1"},
+			expectedVerdict: VerdictFailure,
+		},
+		{
+			name:            "success-stopped by max runs",
+			inputMaxRuns:    1,
+			expectedSample:  Sample{Input: "1 9 3 8 2\n", Output: "23\n", Name: "stress_1"},
+			expectedVerdict: VerdictFailure,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream bytes.Buffer
+			c := &Checker{commander: &sumCommander{}, outStream: &outStream}
+			option := ShrinkOption{Reference: "brute", Shrinkers: shrinkers, Validator: test.inputValidator, MaxRuns: test.inputMaxRuns, TimeLimit: dummyTimeLimit}
+			if test.inputShrinkers != nil {
+				option.Shrinkers = test.inputShrinkers
+			}
+			if test.inputSpec != "" {
+				option.Spec = mustParseSpec(t, test.inputSpec)
+			}
+
+			shrunk, shrunkResult := c.Shrink(dummyRawCommand, sample, result, &ExactJudge{}, option)
+			if shrunk != test.expectedSample {
+				t.Fatalf("sample wrong. want=%+v, got=%+v", test.expectedSample, shrunk)
+			}
+			if shrunkResult.Verdict != test.expectedVerdict {
+				t.Fatalf("verdict wrong. want=%s, got=%s", test.expectedVerdict, shrunkResult.Verdict)
+			}
+		})
+	}
+}
+
+func TestChecker_ShrinkInput(t *testing.T) {
+	option := ShrinkOption{Reference: "brute", Shrinkers: []Shrinker{&LineShrinker{}, &TokenShrinker{}, &NumberShrinker{}}, TimeLimit: dummyTimeLimit}
+
+	var outStream bytes.Buffer
+	c := &Checker{commander: &sumCommander{}, formatter: NewHumanFormatter(&outStream, DiffOption{}), outStream: &outStream}
+	shrunk, result, err := c.ShrinkInput(dummyRawCommand, Sample{Input: "3\n1 2 3\n", Name: "big"}, &ExactJudge{}, option)
+	if err != nil || shrunk != nil || result != nil {
+		t.Fatalf("input should not be shrunk if the program does not fail. got: %+v, %+v, %v", shrunk, result, err)
+	}
+
+	outStream.Reset()
+	shrunk, result, err = c.ShrinkInput(dummyRawCommand, Sample{Input: "3\n1 20 3\n", Name: "big"}, &ExactJudge{}, option)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	expected := Sample{Input: "7\n", Output: "7\n", Name: "big"}
+	if shrunk == nil || *shrunk != expected {
+		t.Fatalf("sample wrong. want=%+v, got=%+v", expected, shrunk)
+	}
+	if result.Verdict != VerdictFailure {
+		t.Fatalf("verdict wrong. want=%s, got=%s", VerdictFailure, result.Verdict)
+	}
+	if !strings.Contains(outStream.String(), "local big: FAILURE") {
+		t.Fatalf("expect '%s' to contain 'local big: FAILURE'", outStream.String())
+	}
+
+	option.Validator = "validator"
+	_, _, err = c.ShrinkInput(dummyRawCommand, Sample{Input: "3\n1 20\n", Name: "big"}, &ExactJudge{}, option)
+	if err == nil || !strings.Contains(err.Error(), "input is rejected by the validator") {
+		t.Fatalf("invalid input should be an error. got: %v", err)
+	}
+
+	option.Validator = ""
+	option.Spec = mustParseSpec(t, "N int [1,5]; A [N]int [0,9]")
+	_, _, err = c.ShrinkInput(dummyRawCommand, Sample{Input: "3\n1 20 3\n", Name: "big"}, &ExactJudge{}, option)
+	if err == nil || !strings.Contains(err.Error(), "input does not follow the spec") {
+		t.Fatalf("input not following the spec should be an error. got: %v", err)
+	}
+}
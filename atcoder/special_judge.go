@@ -0,0 +1,72 @@
+package atcoder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mui87/atctest/commander"
+)
+
+const (
+	JudgeSpecial = "special"
+
+	specialJudgeTimeout = 10 * time.Second
+)
+
+// SpecialJudge delegates judging to a checker program written by user.
+// like testlib, the checker is executed as `<command> <input file> <actual output file> <expected output file>`
+// and its exit code decides whether the output is accepted. stderr of the checker is shown as the judge message.
+type SpecialJudge struct {
+	command   string
+	commander commander.Commander
+}
+
+func NewSpecialJudge(command string) *SpecialJudge {
+	return &SpecialJudge{
+		command:   command,
+		commander: commander.NewExternal(),
+	}
+}
+
+func (j *SpecialJudge) Name() string {
+	return JudgeSpecial
+}
+
+func (j *SpecialJudge) Judge(sample Sample, actual string) (bool, string, error) {
+	dirPath, err := ioutil.TempDir("", "atctest")
+	if err != nil {
+		return false, "", fmt.Errorf("failed to create temporary directory for special judge: %s", err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	inputFilePath := path.Join(dirPath, "input.txt")
+	actualFilePath := path.Join(dirPath, "output.txt")
+	expectedFilePath := path.Join(dirPath, "answer.txt")
+	for filePath, content := range map[string]string{
+		inputFilePath:    sample.Input,
+		actualFilePath:   actual,
+		expectedFilePath: sample.Output,
+	} {
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			return false, "", fmt.Errorf("failed to create file for special judge: %s", err)
+		}
+	}
+
+	command := fmt.Sprintf("%s '%s' '%s' '%s'", j.command, inputFilePath, actualFilePath, expectedFilePath)
+	result, err := j.commander.Run(command, "", commander.Limit{Time: specialJudgeTimeout})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to execute special judge: %s", err)
+	}
+	if result.TimedOut {
+		return false, "", fmt.Errorf("special judge did not finish in %s", specialJudgeTimeout)
+	}
+	if result.Signal != "" {
+		return false, "", fmt.Errorf("special judge was terminated by %s: %s", result.Signal, result.Stderr)
+	}
+
+	return result.ExitCode == 0, strings.TrimRight(result.Stderr, "\n"), nil
+}
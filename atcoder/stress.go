@@ -0,0 +1,130 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// the generator and the reference are not judged, so they are given generous time
+const stressHelperTimeLimit = 10 * time.Second
+
+// StressOption is the setting of stress testing.
+type StressOption struct {
+	// command printing a random input. the seed is passed as its argument. e.g.) 'python gen.py'
+	Generator string
+	// spec of random inputs used instead of the generator
+	Spec *Spec
+	// command of the naive solution whose output is expected. e.g.) 'python brute.py'
+	Reference string
+	// max number of inputs tried
+	Iterations int
+	// seed of the first input. the seed is incremented for each iteration
+	Seed int64
+	// the counterexample is shrunk by them in order. it is not shrunk if empty
+	Shrinkers []Shrinker
+	// command rejecting invalid inputs while shrinking. see ShrinkOption
+	Validator string
+	// max number of runs of the program for shrinking. unlimited if 0
+	MaxShrinkRuns int
+
+	TimeLimit   time.Duration
+	MemoryLimit int64
+}
+
+// StressResult is the result of stress testing.
+type StressResult struct {
+	Summary Summary
+	// the first input on which the program fails with the output of the reference. nil if not found
+	Counterexample *Sample
+	Seed           int64
+}
+
+// Stress runs the program and the reference on random inputs from the generator until the program fails.
+// the counterexample is shrunk by the shrinkers and reported by the formatter.
+func (c *Checker) Stress(command string, option StressOption, judge Judge) (*StressResult, error) {
+	if (option.Generator == "" && option.Spec == nil) || option.Reference == "" {
+		return nil, errors.New("both of the generator (or the spec of inputs) and the reference are required for stress testing")
+	}
+
+	summary := Summary{}
+	for i := 0; i < option.Iterations; i++ {
+		seed := option.Seed + int64(i)
+		_, _ = fmt.Fprintf(c.outStream, "\rstress: %d/%d", i+1, option.Iterations)
+
+		input, err := c.generateInput(option, seed)
+		if err != nil {
+			_, _ = fmt.Fprintln(c.outStream)
+			return nil, fmt.Errorf("%s (seed %d)", err, seed)
+		}
+		expected, err := c.runHelper("reference", option.Reference, input)
+		if err != nil {
+			_, _ = fmt.Fprintln(c.outStream)
+			return nil, fmt.Errorf("%s (seed %d)\ninput:\n%s", err, seed, input)
+		}
+
+		sample := Sample{Input: input, Output: expected, Name: fmt.Sprintf("stress_%d", seed)}
+		result := c.checkOne(command, sample, judge, option.TimeLimit, option.MemoryLimit)
+		summary[result.Verdict]++
+		if result.Verdict == VerdictSuccess {
+			continue
+		}
+
+		_, _ = fmt.Fprintln(c.outStream)
+		if len(option.Shrinkers) > 0 {
+			sample, result = c.Shrink(command, sample, result, judge, ShrinkOption{
+				Reference:   option.Reference,
+				Shrinkers:   option.Shrinkers,
+				Validator:   option.Validator,
+				Spec:        option.Spec,
+				MaxRuns:     option.MaxShrinkRuns,
+				TimeLimit:   option.TimeLimit,
+				MemoryLimit: option.MemoryLimit,
+			})
+		}
+		c.reportOne(i+1, sample, result, judge, option.TimeLimit, option.MemoryLimit)
+		return &StressResult{Summary: summary, Counterexample: &sample, Seed: seed}, nil
+	}
+
+	_, _ = fmt.Fprintln(c.outStream)
+	_, _ = fmt.Fprintf(c.outStream, "no counterexample found in %d inputs\n", option.Iterations)
+	return &StressResult{Summary: summary}, nil
+}
+
+// generateInput returns the random input for the seed from the spec, or from the generator if the spec is not given.
+func (c *Checker) generateInput(option StressOption, seed int64) (string, error) {
+	if option.Spec != nil {
+		return option.Spec.Generate(seed)
+	}
+	return c.runHelper("generator", fmt.Sprintf("%s %d", option.Generator, seed), "")
+}
+
+// runHelper runs the generator or the reference and returns its stdout.
+func (c *Checker) runHelper(role, command, stdin string) (string, error) {
+	result, err := c.commander.Run(command, stdin, commander.Limit{Time: stressHelperTimeLimit})
+	if err != nil {
+		return "", fmt.Errorf("failed to run the %s: %s", role, err)
+	}
+	if result.TimedOut {
+		return "", fmt.Errorf("the %s did not finish in %s", role, stressHelperTimeLimit)
+	}
+	if !result.Succeeded() {
+		return "", fmt.Errorf("the %s exited with code %d:\n%s", role, result.ExitCode, tailLines(result.Stderr, stderrTailLines))
+	}
+	return result.Stdout, nil
+}
+
+// reportOne reports the result of a single input found by stress testing or shrinking.
+func (c *Checker) reportOne(index int, sample Sample, result *SampleResult, judge Judge, timeLimit time.Duration, memoryLimit int64) {
+	c.formatter.Begin(CheckSettings{
+		Judge:               judge.Name(),
+		Samples:             1,
+		TimeLimit:           timeLimit,
+		MemoryLimit:         memoryLimit,
+		MemoryLimitEnforced: c.enforceMemoryLimit,
+	})
+	c.formatter.Sample(index, sample, result)
+	c.formatter.End()
+}
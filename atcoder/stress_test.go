@@ -0,0 +1,141 @@
+package atcoder
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// stressCommander emulates the generator printing its seed, the reference doubling the input
+// and the solution which is wrong for inputs larger than wrongFrom.
+type stressCommander struct {
+	wrongFrom     int
+	referenceFail bool
+}
+
+func (s *stressCommander) Run(command, stdin string, limit commander.Limit) (*commander.Result, error) {
+	switch {
+	case strings.HasPrefix(command, "gen "):
+		return &commander.Result{Stdout: strings.TrimPrefix(command, "gen ") + "\n"}, nil
+	case command == "brute":
+		if s.referenceFail {
+			return &commander.Result{ExitCode: 1, Stderr: "brute is broken\n"}, nil
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(stdin))
+		return &commander.Result{Stdout: strconv.Itoa(2*n) + "\n"}, nil
+	default:
+		n, _ := strconv.Atoi(strings.TrimSpace(stdin))
+		if n >= s.wrongFrom {
+			return &commander.Result{Stdout: strconv.Itoa(2*n+1) + "\n"}, nil
+		}
+		return &commander.Result{Stdout: strconv.Itoa(2*n) + "\n"}, nil
+	}
+}
+
+func TestChecker_Stress(t *testing.T) {
+	tests := []struct {
+		name                   string
+		inputIterations        int
+		mockWrongFrom          int
+		mockReferenceFail      bool
+		expectedSummary        Summary
+		expectedCounterexample *Sample
+		expectedOutput         string
+		expectedErrMsg         string
+	}{
+		{
+			name:                   "success-counterexample found",
+			inputIterations:        100,
+			mockWrongFrom:          13,
+			expectedSummary:        Summary{VerdictSuccess: 3, VerdictFailure: 1},
+			expectedCounterexample: &Sample{Input: "13\n", Output: "26\n", Name: "stress_13"},
+			expectedOutput:         "local stress_13: FAILURE",
+		},
+		{
+			name:            "success-counterexample not found",
+			inputIterations: 5,
+			mockWrongFrom:   100,
+			expectedSummary: Summary{VerdictSuccess: 5},
+			expectedOutput:  "no counterexample found in 5 inputs",
+		},
+		{
+			name:              "failure-reference fails",
+			inputIterations:   5,
+			mockReferenceFail: true,
+			expectedErrMsg:    "the reference exited with code 1:\nbrute is broken\n (seed 10)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream bytes.Buffer
+			c := &Checker{
+				commander: &stressCommander{wrongFrom: test.mockWrongFrom, referenceFail: test.mockReferenceFail},
+				formatter: NewHumanFormatter(&outStream, DiffOption{}),
+				outStream: &outStream,
+			}
+			option := StressOption{Generator: "gen", Reference: "brute", Iterations: test.inputIterations, Seed: 10, TimeLimit: dummyTimeLimit}
+
+			result, err := c.Stress(dummyRawCommand, option, &ExactJudge{})
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			if result.Summary.String() != test.expectedSummary.String() || result.Summary.Total() != test.expectedSummary.Total() {
+				t.Errorf("summary wrong. want=%v, got=%v", test.expectedSummary, result.Summary)
+			}
+			if test.expectedCounterexample == nil {
+				if result.Counterexample != nil {
+					t.Errorf("counterexample should be nil. got=%+v", result.Counterexample)
+				}
+			} else if result.Counterexample == nil || *result.Counterexample != *test.expectedCounterexample {
+				t.Errorf("counterexample wrong. want=%+v, got=%+v", test.expectedCounterexample, result.Counterexample)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Errorf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestChecker_Stress_spec(t *testing.T) {
+	spec, err := ParseSpec("N int [1,20]")
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	var outStream bytes.Buffer
+	c := &Checker{
+		commander: &stressCommander{wrongFrom: 15},
+		formatter: NewHumanFormatter(&outStream, DiffOption{}),
+		outStream: &outStream,
+	}
+	option := StressOption{Spec: spec, Reference: "brute", Iterations: 1000, Seed: 1, TimeLimit: dummyTimeLimit}
+
+	result, err := c.Stress(dummyRawCommand, option, &ExactJudge{})
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if result.Counterexample == nil {
+		t.Fatal("counterexample should be found")
+	}
+	expected, _ := spec.Generate(result.Seed)
+	if result.Counterexample.Input != expected {
+		t.Fatalf("counterexample should be generated from the spec. want=%q, got=%q", expected, result.Counterexample.Input)
+	}
+	if n, _ := strconv.Atoi(strings.TrimSpace(expected)); n < 15 {
+		t.Fatalf("program should not fail on %d", n)
+	}
+}
@@ -0,0 +1,244 @@
+package atcoder
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// sampleReport is the structured result of a sample shared by machine-readable formats.
+type sampleReport struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Local    bool    `json:"local"`
+	Verdict  Verdict `json:"verdict"`
+	Time     int64   `json:"time_ms"`
+	CPUTime  int64   `json:"cpu_time_ms"`
+	Memory   int64   `json:"memory_kb"`
+	ExitCode int     `json:"exit_code"`
+	Signal   string  `json:"signal,omitempty"`
+	Message  string  `json:"message,omitempty"`
+	Input    string  `json:"input"`
+	Expected string  `json:"expected"`
+	Actual   string  `json:"actual"`
+	Stderr   string  `json:"stderr"`
+}
+
+func newSampleReport(index int, sample Sample, result *SampleResult) sampleReport {
+	return sampleReport{
+		Index:    index,
+		Name:     sample.Label(index),
+		Local:    sample.Name != "",
+		Verdict:  result.Verdict,
+		Time:     result.Time.Milliseconds(),
+		CPUTime:  result.CPUTime.Milliseconds(),
+		Memory:   result.Memory / 1024,
+		ExitCode: result.ExitCode,
+		Signal:   result.Signal,
+		Message:  result.Message,
+		Input:    sample.Input,
+		Expected: sample.Output,
+		Actual:   result.Output,
+		Stderr:   result.Stderr,
+	}
+}
+
+// JSONFormatter writes a single JSON object containing all results after samples are checked.
+type JSONFormatter struct {
+	w        io.Writer
+	settings CheckSettings
+	samples  []sampleReport
+}
+
+type jsonReport struct {
+	Judge       string         `json:"judge"`
+	TimeLimit   int64          `json:"time_limit_ms"`
+	MemoryLimit int64          `json:"memory_limit_kb"`
+	Success     bool           `json:"success"`
+	Samples     []sampleReport `json:"samples"`
+}
+
+func (f *JSONFormatter) Begin(settings CheckSettings) {
+	f.settings = settings
+	f.samples = make([]sampleReport, 0, settings.Samples)
+}
+
+func (f *JSONFormatter) Sample(index int, sample Sample, result *SampleResult) {
+	f.samples = append(f.samples, newSampleReport(index, sample, result))
+}
+
+func (f *JSONFormatter) End() {
+	report := jsonReport{
+		Judge:       f.settings.Judge,
+		TimeLimit:   f.settings.TimeLimit.Milliseconds(),
+		MemoryLimit: f.settings.MemoryLimit / 1024,
+		Success:     true,
+		Samples:     f.samples,
+	}
+	for _, s := range f.samples {
+		if s.Verdict != VerdictSuccess {
+			report.Success = false
+		}
+	}
+
+	encoder := json.NewEncoder(f.w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+}
+
+// JUnitFormatter writes results as a JUnit XML test suite, where each sample is a test case.
+// ERROR verdicts are reported as errors and other unsuccessful verdicts as failures.
+type JUnitFormatter struct {
+	w        io.Writer
+	settings CheckSettings
+	suite    junitTestSuite
+	total    time.Duration
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (f *JUnitFormatter) Begin(settings CheckSettings) {
+	f.settings = settings
+	f.suite = junitTestSuite{Name: "atctest (" + settings.Judge + ")"}
+}
+
+func (f *JUnitFormatter) Sample(index int, sample Sample, result *SampleResult) {
+	testCase := junitTestCase{
+		Name:      sample.Label(index),
+		Classname: "atctest",
+		Time:      formatSeconds(result.Time),
+		SystemOut: result.Output,
+		SystemErr: result.Stderr,
+	}
+
+	switch result.Verdict {
+	case VerdictSuccess:
+	case VerdictError:
+		f.suite.Errors++
+		testCase.Error = &junitProblem{Message: result.Message, Type: string(result.Verdict)}
+	default:
+		f.suite.Failures++
+		var text strings.Builder
+		text.WriteString("input:\n" + sample.Input)
+		text.WriteString("expected output:\n" + sample.Output)
+		text.WriteString("actual output:\n" + result.Output)
+		if result.Message != "" {
+			text.WriteString("judge message:\n" + result.Message + "\n")
+		}
+		testCase.Failure = &junitProblem{Message: describeVerdict(result, f.settings), Type: string(result.Verdict), Text: text.String()}
+	}
+
+	f.suite.Tests++
+	f.total += result.Time
+	f.suite.Cases = append(f.suite.Cases, testCase)
+}
+
+func (f *JUnitFormatter) End() {
+	f.suite.Time = formatSeconds(f.total)
+
+	_, _ = io.WriteString(f.w, xml.Header)
+	encoder := xml.NewEncoder(f.w)
+	encoder.Indent("", "  ")
+	_ = encoder.Encode(junitTestSuites{Suites: []junitTestSuite{f.suite}})
+	_, _ = fmt.Fprintln(f.w)
+}
+
+// TAPFormatter writes results in Test Anything Protocol version 13.
+// details of failed samples are attached as YAML blocks, whose strings are written in JSON style.
+type TAPFormatter struct {
+	w        io.Writer
+	settings CheckSettings
+}
+
+func (f *TAPFormatter) Begin(settings CheckSettings) {
+	f.settings = settings
+	_, _ = fmt.Fprintln(f.w, "TAP version 13")
+	_, _ = fmt.Fprintf(f.w, "1..%d\n", settings.Samples)
+}
+
+func (f *TAPFormatter) Sample(index int, sample Sample, result *SampleResult) {
+	if result.Verdict == VerdictSuccess {
+		_, _ = fmt.Fprintf(f.w, "ok %d - %s # time: %d ms, memory: %d KB\n", index, sample.Label(index), result.Time.Milliseconds(), result.Memory/1024)
+		return
+	}
+
+	_, _ = fmt.Fprintf(f.w, "not ok %d - %s # %s\n", index, sample.Label(index), describeVerdict(result, f.settings))
+	report := newSampleReport(index, sample, result)
+	_, _ = fmt.Fprintln(f.w, "  ---")
+	_, _ = fmt.Fprintf(f.w, "  verdict: %s\n", report.Verdict)
+	_, _ = fmt.Fprintf(f.w, "  time_ms: %d\n", report.Time)
+	_, _ = fmt.Fprintf(f.w, "  cpu_time_ms: %d\n", report.CPUTime)
+	_, _ = fmt.Fprintf(f.w, "  memory_kb: %d\n", report.Memory)
+	_, _ = fmt.Fprintf(f.w, "  exit_code: %d\n", report.ExitCode)
+	for _, field := range []struct{ key, value string }{
+		{"signal", report.Signal},
+		{"message", report.Message},
+		{"input", report.Input},
+		{"expected", report.Expected},
+		{"actual", report.Actual},
+		{"stderr", report.Stderr},
+	} {
+		if field.value == "" && (field.key == "signal" || field.key == "message") {
+			continue
+		}
+		quoted, _ := json.Marshal(field.value)
+		_, _ = fmt.Fprintf(f.w, "  %s: %s\n", field.key, quoted)
+	}
+	_, _ = fmt.Fprintln(f.w, "  ...")
+}
+
+func (f *TAPFormatter) End() {}
+
+// describeVerdict returns a one-line description of an unsuccessful result.
+func describeVerdict(result *SampleResult, settings CheckSettings) string {
+	switch result.Verdict {
+	case VerdictFailure:
+		return fmt.Sprintf("%s (%s)", result.Verdict, settings.Judge)
+	case VerdictTLE:
+		return fmt.Sprintf("%s (time limit: %d ms)", result.Verdict, settings.TimeLimit.Milliseconds())
+	case VerdictMLE:
+		return fmt.Sprintf("%s (memory limit: %d KB)", result.Verdict, settings.MemoryLimit/1024)
+	case VerdictRE:
+		if result.Signal != "" {
+			return fmt.Sprintf("%s (%s)", result.Verdict, result.Signal)
+		}
+		return fmt.Sprintf("%s (exit code %d)", result.Verdict, result.ExitCode)
+	default:
+		return fmt.Sprintf("%s: %s", result.Verdict, result.Message)
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,98 @@
+package atcoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	formatterTestSettings = CheckSettings{Judge: JudgeExact, Samples: 2, TimeLimit: dummyTimeLimit, MemoryLimit: dummyMemoryLimit}
+	formatterTestSamples  = []Sample{
+		{Input: "0 1\n", Output: "1\n"},
+		{Input: "1 2\n", Output: "3\n", Name: "small"},
+	}
+	formatterTestResults = []*SampleResult{
+		{Verdict: VerdictSuccess, Output: "1\n", Time: 12 * time.Millisecond, Memory: 2048},
+		{Verdict: VerdictFailure, Output: "99\n", Stderr: "debug\n", Time: 3 * time.Millisecond},
+	}
+)
+
+func writeFormatterTestResults(f Formatter) {
+	f.Begin(formatterTestSettings)
+	for i, sample := range formatterTestSamples {
+		f.Sample(i+1, sample, formatterTestResults[i])
+	}
+	f.End()
+}
+
+func TestJSONFormatter(t *testing.T) {
+	var out bytes.Buffer
+	writeFormatterTestResults(&JSONFormatter{w: &out})
+
+	var report jsonReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("output should be valid json: %s\n%s", err, out.String())
+	}
+	if report.Success {
+		t.Fatal("success wrong. want=false, got=true")
+	}
+	if len(report.Samples) != 2 {
+		t.Fatalf("number of samples wrong. want=2, got=%d", len(report.Samples))
+	}
+	expected := sampleReport{Index: 2, Name: "local small", Local: true, Verdict: VerdictFailure, Time: 3, Input: "1 2\n", Expected: "3\n", Actual: "99\n", Stderr: "debug\n"}
+	if report.Samples[1] != expected {
+		t.Fatalf("sample wrong. want=%+v, got=%+v", expected, report.Samples[1])
+	}
+}
+
+func TestJUnitFormatter(t *testing.T) {
+	var out bytes.Buffer
+	writeFormatterTestResults(&JUnitFormatter{w: &out})
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(out.Bytes(), &suites); err != nil {
+		t.Fatalf("output should be valid xml: %s\n%s", err, out.String())
+	}
+	suite := suites.Suites[0]
+	if suite.Tests != 2 || suite.Failures != 1 || suite.Errors != 0 {
+		t.Fatalf("counts wrong. want=2/1/0, got=%d/%d/%d", suite.Tests, suite.Failures, suite.Errors)
+	}
+	if suite.Time != "0.015" {
+		t.Fatalf("time wrong. want=0.015, got=%s", suite.Time)
+	}
+	if suite.Cases[0].Failure != nil {
+		t.Fatal("successful sample should not have failure")
+	}
+	if failure := suite.Cases[1].Failure; failure == nil || failure.Type != "FAILURE" || !strings.Contains(failure.Text, "actual output:\n99\n") {
+		t.Fatalf("failure wrong. got=%+v", failure)
+	}
+}
+
+func TestTAPFormatter(t *testing.T) {
+	var out bytes.Buffer
+	writeFormatterTestResults(&TAPFormatter{w: &out})
+
+	expected := `TAP version 13
+1..2
+ok 1 - sample 1 # time: 12 ms, memory: 2 KB
+not ok 2 - local small # FAILURE (exact)
+  ---
+  verdict: FAILURE
+  time_ms: 3
+  cpu_time_ms: 0
+  memory_kb: 0
+  exit_code: 0
+  input: "1 2\n"
+  expected: "3\n"
+  actual: "99\n"
+  stderr: "debug\n"
+  ...
+`
+	if out.String() != expected {
+		t.Fatalf("output wrong. want=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,228 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+var (
+	submissionURLRegexp = regexp.MustCompile(`/contests/[^/]+/submissions/\d+$`)
+	progressRegexp      = regexp.MustCompile(`^(\d+)\s*/\s*(\d+)\s*(\S*)$`)
+	execTimeRegexp      = regexp.MustCompile(`^(\d+)\s*ms$`)
+	execMemoryRegexp    = regexp.MustCompile(`^(\d+)\s*KB$`)
+)
+
+const submissionWatchTimeout = 10 * time.Minute
+
+// SubmissionStatus is the judge status of a submission shown on its page.
+type SubmissionStatus struct {
+	Result   string // e.g.) "AC", "WA", "WJ". the worst result so far while judging
+	Judged   int    // number of judged cases while judging
+	Total    int
+	Finished bool
+	Time     time.Duration
+	Memory   int64 // in bytes
+}
+
+// Verdict converts the result of AtCoder to the verdict of atctest.
+func (s *SubmissionStatus) Verdict() Verdict {
+	switch s.Result {
+	case "AC":
+		return VerdictSuccess
+	case "WA", "OLE":
+		return VerdictFailure
+	case "TLE":
+		return VerdictTLE
+	case "MLE":
+		return VerdictMLE
+	case "RE":
+		return VerdictRE
+	case "CE":
+		return VerdictCE
+	default:
+		return VerdictError
+	}
+}
+
+func (s *SubmissionStatus) String() string {
+	if s.Finished {
+		return fmt.Sprintf("%s [time: %d ms, memory: %d KB]", s.Result, s.Time.Milliseconds(), s.Memory/1024)
+	}
+	if s.Total > 0 {
+		return strings.TrimSpace(fmt.Sprintf("%d/%d %s", s.Judged, s.Total, s.Result))
+	}
+	return s.Result
+}
+
+type submitLanguage struct {
+	id   string
+	name string
+}
+
+// Submit posts the source code to the submit form of the contest and returns the url of the submission.
+// language is either the id of the language or a part of its name on the submit page. e.g.) "4003", "C++ (GCC"
+// the client should be logged in before submitting.
+func (c *Client) Submit(contestURL, problemURL, language, sourceCode string) (string, error) {
+	taskScreenName := problemURL[strings.LastIndex(strings.TrimRight(problemURL, "/"), "/")+1:]
+	submitURL := contestURL + "/submit"
+
+	var (
+		csrfToken     string
+		languages     []submitLanguage
+		posted        bool
+		submissionURL string
+	)
+	c.collector.OnHTML(`input[name="csrf_token"]`, func(e *colly.HTMLElement) {
+		if csrfToken == "" {
+			csrfToken = e.Attr("value")
+		}
+	})
+	c.collector.OnHTML(`select[name="data.LanguageId"] > option`, func(e *colly.HTMLElement) {
+		if id := e.Attr("value"); id != "" && !posted {
+			languages = append(languages, submitLanguage{id: id, name: strings.TrimSpace(e.Text)})
+		}
+	})
+	c.collector.OnHTML(`a[href]`, func(e *colly.HTMLElement) {
+		href := e.Request.AbsoluteURL(e.Attr("href"))
+		if posted && submissionURL == "" && submissionURLRegexp.MatchString(href) {
+			submissionURL = href
+		}
+	})
+
+	if err := c.collector.Visit(submitURL + "?taskScreenName=" + taskScreenName); err != nil {
+		return "", fmt.Errorf("could not get HTML: %s", submitURL)
+	}
+	if csrfToken == "" || len(languages) == 0 {
+		return "", errors.New("could not find the submit form. you may not be logged in")
+	}
+
+	languageID, err := selectLanguage(languages, language)
+	if err != nil {
+		return "", err
+	}
+
+	posted = true
+	reqBody := map[string]string{
+		"data.TaskScreenName": taskScreenName,
+		"data.LanguageId":     languageID,
+		"sourceCode":          sourceCode,
+		"csrf_token":          csrfToken,
+	}
+	if err := c.collector.Post(submitURL, reqBody); err != nil {
+		return "", fmt.Errorf("submit error: %s", err)
+	}
+	if submissionURL == "" {
+		return "", errors.New("submit error: could not find the submission. the source code may be rejected")
+	}
+
+	return submissionURL, nil
+}
+
+// selectLanguage returns the id of the language which matches the id or contains the name (case-insensitive).
+// languages are searched in order of the submit page.
+func selectLanguage(languages []submitLanguage, query string) (string, error) {
+	if query == "" {
+		return "", errors.New("specify the language to submit. e.g.) -submitlang 'C++ (GCC'")
+	}
+
+	for _, l := range languages {
+		if l.id == query {
+			return l.id, nil
+		}
+	}
+	for _, l := range languages {
+		if strings.Contains(strings.ToLower(l.name), strings.ToLower(query)) {
+			return l.id, nil
+		}
+	}
+
+	names := make([]string, len(languages))
+	for i, l := range languages {
+		names[i] = fmt.Sprintf("%s (%s)", l.id, l.name)
+	}
+	return "", fmt.Errorf("could not find the language '%s'. available languages: %s", query, strings.Join(names, ", "))
+}
+
+// WatchSubmission polls the page of the submission every interval until its judge finishes.
+// onUpdate is called whenever the status changes.
+func (c *Client) WatchSubmission(submissionURL string, interval time.Duration, onUpdate func(status *SubmissionStatus)) (*SubmissionStatus, error) {
+	// the same page is visited repeatedly, which the main collector does not allow
+	collector := c.collector.Clone()
+	collector.AllowURLRevisit = true
+
+	var fields map[string]string
+	collector.OnHTML(`tr`, func(e *colly.HTMLElement) {
+		fields[strings.TrimSpace(e.ChildText("th"))] = strings.TrimSpace(e.ChildText("td"))
+	})
+
+	var last string
+	deadline := time.Now().Add(submissionWatchTimeout)
+	for {
+		fields = map[string]string{}
+		if err := collector.Visit(submissionURL); err != nil {
+			return nil, fmt.Errorf("could not get HTML: %s", submissionURL)
+		}
+
+		status, err := parseSubmissionStatus(fields)
+		if err != nil {
+			return nil, err
+		}
+		if s := status.String(); s != last {
+			last = s
+			onUpdate(status)
+		}
+		if status.Finished {
+			return status, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("judge did not finish in %s. see %s", submissionWatchTimeout, submissionURL)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// parseSubmissionStatus parses rows of the submission info table, whose headers are in English or Japanese.
+func parseSubmissionStatus(fields map[string]string) (*SubmissionStatus, error) {
+	lookup := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := fields[key]; ok {
+				return value
+			}
+		}
+		return ""
+	}
+
+	result := lookup("Status", "ステータス", "結果")
+	if result == "" {
+		return nil, errors.New("could not find the status of the submission")
+	}
+
+	status := &SubmissionStatus{Result: result}
+	if m := progressRegexp.FindStringSubmatch(result); m != nil {
+		status.Judged, _ = strconv.Atoi(m[1])
+		status.Total, _ = strconv.Atoi(m[2])
+		status.Result = m[3]
+		return status, nil
+	}
+	if result == "WJ" || result == "WR" || result == "Judging" {
+		return status, nil
+	}
+
+	status.Finished = true
+	if m := execTimeRegexp.FindStringSubmatch(lookup("Exec Time", "実行時間")); m != nil {
+		ms, _ := strconv.ParseInt(m[1], 10, 64)
+		status.Time = time.Duration(ms) * time.Millisecond
+	}
+	if m := execMemoryRegexp.FindStringSubmatch(lookup("Memory", "メモリ")); m != nil {
+		kb, _ := strconv.ParseInt(m[1], 10, 64)
+		status.Memory = kb * 1024
+	}
+	return status, nil
+}
@@ -0,0 +1,194 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_Submit(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputLanguage string
+
+		mockSubmitHTMLFile string
+		mockRedirectPath   string
+		mockResultHTMLFile string
+		expectedLanguageID string
+		expectedSubmission string
+		expectedErrMsg     string
+	}{
+		{
+			name:               "success-language id",
+			inputLanguage:      "4006",
+			mockSubmitHTMLFile: "abc051_submit.html",
+			mockRedirectPath:   "/contests/abc051/submissions/me",
+			mockResultHTMLFile: "abc051_submissions_me.html",
+			expectedLanguageID: "4006",
+			expectedSubmission: dummyBaseURL + "/contests/abc051/submissions/5432100",
+		},
+		{
+			name:               "success-language name",
+			inputLanguage:      "c++",
+			mockSubmitHTMLFile: "abc051_submit.html",
+			mockRedirectPath:   "/contests/abc051/submissions/me",
+			mockResultHTMLFile: "abc051_submissions_me.html",
+			expectedLanguageID: "4003",
+			expectedSubmission: dummyBaseURL + "/contests/abc051/submissions/5432100",
+		},
+		{
+			name:               "failure-unknown language",
+			inputLanguage:      "cobol",
+			mockSubmitHTMLFile: "abc051_submit.html",
+			expectedErrMsg:     "could not find the language 'cobol'",
+		},
+		{
+			name:               "failure-rejected",
+			inputLanguage:      "4006",
+			mockSubmitHTMLFile: "abc051_submit.html",
+			mockRedirectPath:   "/contests/abc051/submit",
+			mockResultHTMLFile: "abc051_submit.html",
+			expectedLanguageID: "4006",
+			expectedErrMsg:     "could not find the submission",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			submitHTML, err := ioutil.ReadFile(path.Join("testdata", "submit", test.mockSubmitHTMLFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer gock.Off()
+			gock.New(dummyBaseURL).
+				Get("/contests/abc051/submit").
+				MatchParam("taskScreenName", "abc051_c").
+				Reply(http.StatusOK).
+				AddHeader("Content-Type", "text/html").
+				BodyString(string(submitHTML))
+
+			if test.mockRedirectPath != "" {
+				resultHTML, err := ioutil.ReadFile(path.Join("testdata", "submit", test.mockResultHTMLFile))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expectedForm := url.Values{
+					"data.TaskScreenName": {"abc051_c"},
+					"data.LanguageId":     {test.expectedLanguageID},
+					"sourceCode":          {"print(1)\n"},
+					"csrf_token":          {"dummy+csrf/token="},
+				}
+				gock.New(dummyBaseURL).
+					Post("/contests/abc051/submit").
+					MatchType("url").
+					BodyString(expectedForm.Encode()).
+					Reply(http.StatusFound).
+					AddHeader("Location", test.mockRedirectPath)
+				gock.New(dummyBaseURL).
+					Get(test.mockRedirectPath).
+					Reply(http.StatusOK).
+					AddHeader("Content-Type", "text/html").
+					BodyString(string(resultHTML))
+			}
+
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+			submission, err := c.Submit(dummyBaseURL+"/contests/abc051", dummyBaseURL+"/contests/abc051/tasks/abc051_c", test.inputLanguage, "print(1)\n")
+
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if submission != test.expectedSubmission {
+					t.Fatalf("submission URL wrong. want='%s', got='%s'", test.expectedSubmission, submission)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_WatchSubmission(t *testing.T) {
+	tests := []struct {
+		name string
+
+		mockHTMLFiles []string
+
+		expectedUpdates []string
+		expectedVerdict Verdict
+		expectedErrMsg  string
+	}{
+		{
+			name:            "success-accepted",
+			mockHTMLFiles:   []string{"abc051_submission_waiting.html", "abc051_submission_waiting.html", "abc051_submission_judging.html", "abc051_submission_accepted.html"},
+			expectedUpdates: []string{"WJ", "12/45", "AC [time: 23 ms, memory: 3456 KB]"},
+			expectedVerdict: VerdictSuccess,
+		},
+		{
+			name:            "success-time limit exceeded in japanese",
+			mockHTMLFiles:   []string{"abc051_submission_tle.html"},
+			expectedUpdates: []string{"TLE [time: 2103 ms, memory: 10240 KB]"},
+			expectedVerdict: VerdictTLE,
+		},
+		{
+			name:           "failure-not a submission page",
+			mockHTMLFiles:  []string{"abc051_submit.html"},
+			expectedErrMsg: "could not find the status",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer gock.Off()
+			for _, file := range test.mockHTMLFiles {
+				html, err := ioutil.ReadFile(path.Join("testdata", "submit", file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				gock.New(dummyBaseURL).
+					Get("/contests/abc051/submissions/5432100").
+					Reply(http.StatusOK).
+					AddHeader("Content-Type", "text/html").
+					BodyString(string(html))
+			}
+
+			var updates []string
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+			status, err := c.WatchSubmission(dummyBaseURL+"/contests/abc051/submissions/5432100", 0, func(status *SubmissionStatus) {
+				updates = append(updates, status.String())
+			})
+
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if strings.Join(updates, ", ") != strings.Join(test.expectedUpdates, ", ") {
+					t.Fatalf("updates wrong. want=%v, got=%v", test.expectedUpdates, updates)
+				}
+				if status.Verdict() != test.expectedVerdict {
+					t.Fatalf("verdict wrong. want=%s, got=%s", test.expectedVerdict, status.Verdict())
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package atcoder
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Tolerance struct {
+	Absolute float64
+	Relative float64
+}
+
+func (t Tolerance) IsZero() bool {
+	return t.Absolute == 0 && t.Relative == 0
+}
+
+func (t Tolerance) String() string {
+	return fmt.Sprintf("absolute error %g, relative error %g", t.Absolute, t.Relative)
+}
+
+// match compares outputs token by token. tokens which can be parsed as float are regarded as equal
+// when the absolute or relative error is within the tolerance, and other tokens must be exactly equal.
+func (t Tolerance) match(expected, actual string) bool {
+	expectedTokens := strings.Fields(expected)
+	actualTokens := strings.Fields(actual)
+	if len(expectedTokens) != len(actualTokens) {
+		return false
+	}
+
+	for i, e := range expectedTokens {
+		a := actualTokens[i]
+		if e == a {
+			continue
+		}
+
+		ef, err := strconv.ParseFloat(e, 64)
+		if err != nil {
+			return false
+		}
+		af, err := strconv.ParseFloat(a, 64)
+		if err != nil {
+			return false
+		}
+		if math.IsNaN(af) || math.IsInf(af, 0) {
+			return false
+		}
+
+		diff := math.Abs(af - ef)
+		if diff > t.Absolute && diff > t.Relative*math.Abs(ef) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// e.g.) "絶対誤差または相対誤差が 10^{-6} 以下", "absolute or relative error ... is at most 10^{-6}"
+var toleranceRegexp = regexp.MustCompile(`(?s)(?:誤差|error).{0,100}?10\s*\^\s*\{?\s*[-−]\s*(\d+)\s*\}?`)
+
+func parseTolerance(statement string) float64 {
+	matches := toleranceRegexp.FindStringSubmatch(statement)
+	if matches == nil {
+		return 0
+	}
+
+	exp, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0
+	}
+	return math.Pow10(-exp)
+}
@@ -0,0 +1,117 @@
+package atcoder
+
+import (
+	"testing"
+)
+
+func TestTolerance_match(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputTolerance Tolerance
+		inputExpected  string
+		inputActual    string
+		expected       bool
+	}{
+		{
+			name:           "success-exactly same",
+			inputTolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6},
+			inputExpected:  "1.000000\n",
+			inputActual:    "1.000000\n",
+			expected:       true,
+		},
+		{
+			name:           "success-within absolute error",
+			inputTolerance: Tolerance{Absolute: 1e-6},
+			inputExpected:  "0.5\n",
+			inputActual:    "0.5000009\n",
+			expected:       true,
+		},
+		{
+			name:           "success-within relative error",
+			inputTolerance: Tolerance{Relative: 1e-6},
+			inputExpected:  "1000000000\n",
+			inputActual:    "1000000900.0\n",
+			expected:       true,
+		},
+		{
+			name:           "success-multiple tokens and whitespaces",
+			inputTolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6},
+			inputExpected:  "Yes\n1.0 2.0\n",
+			inputActual:    "Yes\n1.0000001   1.9999999",
+			expected:       true,
+		},
+		{
+			name:           "failure-out of error",
+			inputTolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6},
+			inputExpected:  "0.5\n",
+			inputActual:    "0.50001\n",
+			expected:       false,
+		},
+		{
+			name:           "failure-number of tokens differs",
+			inputTolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6},
+			inputExpected:  "1.0 2.0\n",
+			inputActual:    "1.0\n",
+			expected:       false,
+		},
+		{
+			name:           "failure-non numeric token differs",
+			inputTolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6},
+			inputExpected:  "Yes 1.0\n",
+			inputActual:    "No 1.0\n",
+			expected:       false,
+		},
+		{
+			name:           "failure-nan",
+			inputTolerance: Tolerance{Absolute: 1e-6, Relative: 1e-6},
+			inputExpected:  "1.0\n",
+			inputActual:    "nan\n",
+			expected:       false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.inputTolerance.match(test.inputExpected, test.inputActual)
+			if actual != test.expected {
+				t.Fatalf("match wrong. want=%t, got=%t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_parseTolerance(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputStatement string
+		expected       float64
+	}{
+		{
+			name:           "success-japanese",
+			inputStatement: "ジャッジの出力との絶対誤差または相対誤差が 10^{-6} 以下であれば正解と判定される。",
+			expected:       1e-6,
+		},
+		{
+			name:           "success-english",
+			inputStatement: "Your output is considered correct if the absolute or relative error from the judge's output is at most 10^{-9}.",
+			expected:       1e-9,
+		},
+		{
+			name:           "success-unicode minus",
+			inputStatement: "出力は絶対誤差が 10^{−2} 以下であれば許容される。",
+			expected:       1e-2,
+		},
+		{
+			name:           "success-no tolerance",
+			inputStatement: "1 \\leq N \\leq 10^5\n海を眺められる旅館の数を出力せよ。",
+			expected:       0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := parseTolerance(test.inputStatement)
+			if actual != test.expected {
+				t.Fatalf("tolerance wrong. want=%g, got=%g", test.expected, actual)
+			}
+		})
+	}
+}
@@ -3,12 +3,35 @@ package commander
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 type Commander interface {
-	Run(rawCommand, stdin string) (string, error)
+	Run(rawCommand, stdin string, limit Limit) (*Result, error)
+}
+
+type Limit struct {
+	Time   time.Duration
+	Memory int64 // address space in bytes, which is limited by rlimit (ulimit -v) if positive
+}
+
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Signal   string // name of the signal which terminated the command. e.g.) "SIGSEGV"
+	Time     time.Duration
+	CPUTime  time.Duration
+	Memory   int64
+	TimedOut bool
+}
+
+func (r *Result) Succeeded() bool {
+	return r.ExitCode == 0 && r.Signal == ""
 }
 
 type External struct{}
@@ -17,20 +40,128 @@ func NewExternal() *External {
 	return &External{}
 }
 
-func (e *External) Run(rawCommand, stdin string) (string, error) {
-	var errBuf bytes.Buffer
+// Run executes the command with stdin. error is returned only when the command could not be executed,
+// and the exit status of the command is reported in the result.
+// when the time limit is positive and the command does not finish in time,
+// the whole process group of the command is killed and TimedOut of the result is set.
+// descendants left after the command exits are also killed, so that they do not keep outputs open.
+// Memory of the result is the peak RSS of the command in bytes.
+func (e *External) Run(rawCommand, stdin string, limit Limit) (*Result, error) {
+	if limit.Memory > 0 {
+		rawCommand = fmt.Sprintf("ulimit -v %d; %s", limit.Memory/1024, rawCommand)
+	}
+
+	stdout, err := newOutput()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := newOutput()
+	if err != nil {
+		stdout.close()
+		return nil, err
+	}
 
 	cmd := NewCommand(rawCommand)
 	cmd.Stdin = strings.NewReader(stdin)
-	cmd.Stderr = &errBuf
+	cmd.Stdout = stdout.w
+	cmd.Stderr = stderr.w
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		stdout.close()
+		stderr.close()
+		return nil, err
+	}
+	stdout.start()
+	stderr.start()
+	timedOut, err := wait(cmd, limit.Time)
+	elapsed := time.Since(start)
+	killProcessGroup(cmd)
+	outString, errString := stdout.finish(), stderr.finish()
+	if cmd.ProcessState == nil {
+		return nil, err
+	}
+
+	exitCode, signal := exitStatus(cmd.ProcessState)
+	return &Result{
+		Stdout:   outString,
+		Stderr:   errString,
+		ExitCode: exitCode,
+		Signal:   signal,
+		Time:     elapsed,
+		CPUTime:  cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime(),
+		Memory:   maxRSS(cmd.ProcessState),
+		TimedOut: timedOut,
+	}, nil
+}
 
-	out, err := cmd.Output()
+// outputDrainTimeout is how long outputs are read after the command exits,
+// in case a descendant which cannot be killed keeps them open
+const outputDrainTimeout = 100 * time.Millisecond
+
+// output collects what the command writes into a pipe.
+// the pipe is passed to the command as a file, so that waiting for the command does not wait for its descendants.
+type output struct {
+	r, w *os.File
+	buf  bytes.Buffer
+	done chan struct{}
+}
+
+func newOutput() (*output, error) {
+	r, w, err := os.Pipe()
 	if err != nil {
-		return "", fmt.Errorf("%s: %s", err.Error(), errBuf.String())
+		return nil, err
+	}
+	return &output{r: r, w: w, done: make(chan struct{})}, nil
+}
+
+// start reads the pipe in background. the write end is closed because the command has its own copy.
+func (o *output) start() {
+	_ = o.w.Close()
+	go func() {
+		_, _ = io.Copy(&o.buf, o.r)
+		close(o.done)
+	}()
+}
+
+// finish returns the output after the pipe is closed by all writers, or after outputDrainTimeout.
+func (o *output) finish() string {
+	select {
+	case <-o.done:
+	case <-time.After(outputDrainTimeout):
+		_ = o.r.Close()
+		<-o.done
 	}
-	return string(out), nil
+	_ = o.r.Close()
+	return o.buf.String()
+}
+
+func (o *output) close() {
+	_ = o.r.Close()
+	_ = o.w.Close()
 }
 
 func NewCommand(rawCommand string) *exec.Cmd {
-	return exec.Command("/bin/bash", "-c", rawCommand)
+	cmd := exec.Command("/bin/bash", "-c", rawCommand)
+	setProcessGroup(cmd)
+	return cmd
+}
+
+func wait(cmd *exec.Cmd, timeout time.Duration) (bool, error) {
+	if timeout <= 0 {
+		return false, cmd.Wait()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return false, err
+	case <-time.After(timeout):
+		killProcessGroup(cmd)
+		return true, <-done
+	}
 }
@@ -0,0 +1,120 @@
+//go:build !windows
+// +build !windows
+
+package commander
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExternal_Run(t *testing.T) {
+	tests := []struct {
+		name             string
+		inputCommand     string
+		inputStdin       string
+		inputLimit       Limit
+		expectedStdout   string
+		expectedStderr   string
+		expectedExitCode int
+		expectedSignal   string
+		expectedTimedOut bool
+		// the run should finish in this duration
+		expectedMaxTime time.Duration
+	}{
+		{
+			name:           "success-stdin to stdout",
+			inputCommand:   "cat; echo error >&2",
+			inputStdin:     "1 2\n3\n",
+			expectedStdout: "1 2\n3\n",
+			expectedStderr: "error\n",
+		},
+		{
+			name:             "success-exit code",
+			inputCommand:     "echo partial; exit 3",
+			expectedStdout:   "partial\n",
+			expectedExitCode: 3,
+		},
+		{
+			name:             "success-signal of the shell",
+			inputCommand:     "kill -SEGV $$",
+			expectedExitCode: 128 + 11,
+			expectedSignal:   "SIGSEGV",
+		},
+		{
+			name:             "success-signal inferred from the exit code of the child",
+			inputCommand:     "{ bash -c 'kill -FPE $$'; } 2>/dev/null; exit $?",
+			expectedExitCode: 128 + 8,
+			expectedSignal:   "SIGFPE",
+		},
+		{
+			name:             "success-timed out",
+			inputCommand:     "echo started; sleep 5",
+			inputLimit:       Limit{Time: 100 * time.Millisecond},
+			expectedStdout:   "started\n",
+			expectedExitCode: 128 + 9,
+			expectedSignal:   "SIGKILL",
+			expectedTimedOut: true,
+			expectedMaxTime:  2 * time.Second,
+		},
+		{
+			name:            "success-background process keeping stdout open",
+			inputCommand:    "sleep 5 & echo done",
+			expectedStdout:  "done\n",
+			expectedMaxTime: 2 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			result, err := NewExternal().Run(test.inputCommand, test.inputStdin, test.inputLimit)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if test.expectedMaxTime > 0 && time.Since(start) > test.expectedMaxTime {
+				t.Fatalf("run should finish in %s. got: %s", test.expectedMaxTime, time.Since(start))
+			}
+
+			if result.Stdout != test.expectedStdout {
+				t.Errorf("stdout wrong. want=%q, got=%q", test.expectedStdout, result.Stdout)
+			}
+			if result.Stderr != test.expectedStderr {
+				t.Errorf("stderr wrong. want=%q, got=%q", test.expectedStderr, result.Stderr)
+			}
+			if result.ExitCode != test.expectedExitCode {
+				t.Errorf("exit code wrong. want=%d, got=%d", test.expectedExitCode, result.ExitCode)
+			}
+			if result.Signal != test.expectedSignal {
+				t.Errorf("signal wrong. want=%q, got=%q", test.expectedSignal, result.Signal)
+			}
+			if result.TimedOut != test.expectedTimedOut {
+				t.Errorf("timed out wrong. want=%t, got=%t", test.expectedTimedOut, result.TimedOut)
+			}
+		})
+	}
+}
+
+func TestExternal_Run_memory(t *testing.T) {
+	// bash keeps the string of 64 MB in memory
+	command := "x=$(head -c 67108864 /dev/zero | tr '\\0' a); echo ${#x}"
+
+	result, err := NewExternal().Run(command, "", Limit{})
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if result.Stdout != "67108864\n" {
+		t.Fatalf("stdout wrong. want=%q, got=%q", "67108864\n", result.Stdout)
+	}
+	if result.Memory < 64<<20 {
+		t.Errorf("peak RSS should be at least 64 MB. got: %d", result.Memory)
+	}
+
+	result, err = NewExternal().Run(command, "", Limit{Memory: 32 << 20})
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if result.Succeeded() {
+		t.Errorf("run should fail with the memory limit. got: %+v", result)
+	}
+}
@@ -0,0 +1,174 @@
+package commander
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Interactive interface {
+	RunInteractive(rawCommand, interactorCommand string, queryLimit int, timeout time.Duration) (*Interaction, error)
+}
+
+type Interaction struct {
+	Accepted           bool
+	QueryLimitExceeded bool
+	Queries            int
+	Time               time.Duration
+	TimedOut           bool
+	Transcript         string
+	JudgeMessage       string
+}
+
+// RunInteractive connects stdout of the program to stdin of the interactor and vice versa.
+// lines starting with "?" written by the program are counted as queries.
+// the program is accepted when the interactor exits with code 0.
+func (e *External) RunInteractive(rawCommand, interactorCommand string, queryLimit int, timeout time.Duration) (*Interaction, error) {
+	var (
+		solutionErrBuf   bytes.Buffer
+		interactorErrBuf bytes.Buffer
+		transcript       transcript
+	)
+
+	solution := NewCommand(rawCommand)
+	solution.Stderr = &solutionErrBuf
+	solutionIn, err := solution.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	solutionOut, err := solution.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	interactor := NewCommand(interactorCommand)
+	interactor.Stderr = &interactorErrBuf
+	interactorIn, err := interactor.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	interactorOut, err := interactor.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := interactor.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start interactor: %s", err)
+	}
+	if err := solution.Start(); err != nil {
+		killProcessGroup(interactor)
+		_ = interactor.Wait()
+		return nil, err
+	}
+
+	var timer *time.Timer
+	if timeout > 0 {
+		timer = time.AfterFunc(timeout, func() {
+			killProcessGroup(solution)
+			killProcessGroup(interactor)
+		})
+	}
+
+	var (
+		wg                 sync.WaitGroup
+		queries            int
+		queryLimitExceeded bool
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer interactorIn.Close()
+		relay(solutionOut, interactorIn, func(line string) bool {
+			transcript.add("> ", line)
+			if strings.HasPrefix(line, "?") {
+				queries++
+			}
+			if queryLimit > 0 && queries > queryLimit {
+				queryLimitExceeded = true
+				killProcessGroup(solution)
+				killProcessGroup(interactor)
+				return false
+			}
+			return true
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		defer solutionIn.Close()
+		relay(interactorOut, solutionIn, func(line string) bool {
+			transcript.add("< ", line)
+			return true
+		})
+	}()
+	wg.Wait()
+
+	solutionErr := solution.Wait()
+	interactorErr := interactor.Wait()
+	elapsed := time.Since(start)
+	// the timer has already fired if it could not be stopped
+	timedOut := timer != nil && !timer.Stop()
+
+	interaction := &Interaction{
+		Accepted:           interactorErr == nil && !queryLimitExceeded && !timedOut,
+		QueryLimitExceeded: queryLimitExceeded,
+		Queries:            queries,
+		Time:               elapsed,
+		TimedOut:           timedOut,
+		Transcript:         transcript.String(),
+		JudgeMessage:       interactorErrBuf.String(),
+	}
+	if solutionErr != nil && !queryLimitExceeded && !timedOut {
+		return interaction, fmt.Errorf("%s: %s", solutionErr.Error(), solutionErrBuf.String())
+	}
+
+	return interaction, nil
+}
+
+// relay copies lines from src to dst until src is closed, dst is closed or onLine returns false.
+func relay(src io.Reader, dst io.Writer, onLine func(line string) bool) {
+	reader := bufio.NewReader(src)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if !onLine(line) {
+				return
+			}
+			if _, err := io.WriteString(dst, line); err != nil {
+				// the other side has exited. keep reading to record the rest of outputs
+				dst = ioutil.Discard
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+type transcript struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (t *transcript) add(prefix, line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf.WriteString(prefix)
+	t.buf.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		t.buf.WriteString("\n")
+	}
+}
+
+func (t *transcript) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.buf.String()
+}
@@ -0,0 +1,107 @@
+//go:build !windows
+// +build !windows
+
+package commander
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// interactor asks the solution to double the number, and answers 'ok' to each query
+const doublingInteractor = `echo 5; while read line; do case "$line" in "?"*) echo ok;; "! 10") exit 0;; *) exit 1;; esac; done; exit 1`
+
+func TestExternal_RunInteractive(t *testing.T) {
+	tests := []struct {
+		name                       string
+		inputCommand               string
+		inputInteractor            string
+		inputQueryLimit            int
+		inputTimeout               time.Duration
+		expectedAccepted           bool
+		expectedQueries            int
+		expectedQueryLimitExceeded bool
+		expectedTimedOut           bool
+		expectedTranscript         string
+		expectedErrMsg             string
+	}{
+		{
+			name:               "success-accepted",
+			inputCommand:       `read n; echo "? 1"; read r; echo "! $((n*2))"`,
+			inputInteractor:    doublingInteractor,
+			inputTimeout:       5 * time.Second,
+			expectedAccepted:   true,
+			expectedQueries:    1,
+			expectedTranscript: "< 5\n> ? 1\n< ok\n> ! 10\n",
+		},
+		{
+			name:             "success-wrong answer",
+			inputCommand:     `read n; echo "! $((n*3))"`,
+			inputInteractor:  doublingInteractor,
+			inputTimeout:     5 * time.Second,
+			expectedAccepted: false,
+		},
+		{
+			name:                       "success-query limit exceeded",
+			inputCommand:               `read n; while true; do echo "? 1"; read r; done`,
+			inputInteractor:            doublingInteractor,
+			inputQueryLimit:            10,
+			inputTimeout:               5 * time.Second,
+			expectedQueries:            11,
+			expectedQueryLimitExceeded: true,
+		},
+		{
+			name:             "success-timed out",
+			inputCommand:     `read n; sleep 5`,
+			inputInteractor:  doublingInteractor,
+			inputTimeout:     100 * time.Millisecond,
+			expectedTimedOut: true,
+		},
+		{
+			name:            "failure-solution exits with error",
+			inputCommand:    `read n; echo broken >&2; exit 3`,
+			inputInteractor: doublingInteractor,
+			inputTimeout:    5 * time.Second,
+			expectedErrMsg:  "exit status 3: broken",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			interaction, err := NewExternal().RunInteractive(test.inputCommand, test.inputInteractor, test.inputQueryLimit, test.inputTimeout)
+			if time.Since(start) > 2*time.Second {
+				t.Fatalf("interaction should finish in 2s. got: %s", time.Since(start))
+			}
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			if interaction.Accepted != test.expectedAccepted {
+				t.Errorf("accepted wrong. want=%t, got=%t\n%s", test.expectedAccepted, interaction.Accepted, interaction.Transcript)
+			}
+			if test.expectedQueries > 0 && interaction.Queries != test.expectedQueries {
+				t.Errorf("queries wrong. want=%d, got=%d", test.expectedQueries, interaction.Queries)
+			}
+			if interaction.QueryLimitExceeded != test.expectedQueryLimitExceeded {
+				t.Errorf("query limit exceeded wrong. want=%t, got=%t", test.expectedQueryLimitExceeded, interaction.QueryLimitExceeded)
+			}
+			if interaction.TimedOut != test.expectedTimedOut {
+				t.Errorf("timed out wrong. want=%t, got=%t", test.expectedTimedOut, interaction.TimedOut)
+			}
+			if test.expectedTranscript != "" && interaction.Transcript != test.expectedTranscript {
+				t.Errorf("transcript wrong. want=%q, got=%q", test.expectedTranscript, interaction.Transcript)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package commander
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Kill()
+}
+
+func maxRSS(state *os.ProcessState) int64 {
+	return 0
+}
+
+func exitStatus(state *os.ProcessState) (int, string) {
+	return state.ExitCode(), ""
+}
@@ -1,15 +1,23 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/mui87/atctest/app"
+	"github.com/mui87/atctest/atcoder"
 )
 
 const (
 	exitCodeOK = iota
 	exitCodeErr
+	exitCodeWA
+	exitCodeTLE
+	exitCodeMLE
+	exitCodeRE
+	exitCodeCE
+	exitCodeNetwork
 )
 
 func main() {
@@ -25,8 +33,33 @@ func run() int {
 
 	if err := a.Run(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "[ERROR] "+err.Error())
-		return exitCodeErr
+		return exitCode(err)
 	}
 
 	return exitCodeOK
 }
+
+func exitCode(err error) int {
+	var failureErr *app.FailureError
+	if errors.As(err, &failureErr) {
+		switch failureErr.Verdict() {
+		case atcoder.VerdictFailure:
+			return exitCodeWA
+		case atcoder.VerdictTLE:
+			return exitCodeTLE
+		case atcoder.VerdictMLE:
+			return exitCodeMLE
+		case atcoder.VerdictRE:
+			return exitCodeRE
+		case atcoder.VerdictCE:
+			return exitCodeCE
+		}
+	}
+
+	var networkErr *app.NetworkError
+	if errors.As(err, &networkErr) {
+		return exitCodeNetwork
+	}
+
+	return exitCodeErr
+}